@@ -16,6 +16,7 @@ const (
 
 var (
 	options dnstwist.Options
+	topN    int
 	rootCmd = &cobra.Command{
 		Use:     "dnstwist",
 		Short:   "Domain name permutation engine for detecting typo squatting, phishing and corporate espionage",
@@ -32,15 +33,37 @@ var (
 				return fmt.Errorf("error creating engine: %v", err)
 			}
 
-			// Get raw results
-			results, err := engine.GetResults()
-			if err != nil {
-				return fmt.Errorf("error getting results: %v", err)
+			// --top requires sorting the whole result set by confusability
+			// first, so it can't stream; fall back to the buffered path.
+			if topN > 0 {
+				results, err := engine.GetResults()
+				if err != nil {
+					return fmt.Errorf("error getting results: %v", err)
+				}
+				results = results.SortByConfusability()
+				if topN < len(results) {
+					results = results[:topN]
+				}
+				fmt.Print(results.Format(options.Format))
+				return nil
 			}
 
-			// Format and output results
-			output := results.Format(options.Format)
-			fmt.Print(output)
+			// Stream results as they're scanned instead of buffering the
+			// whole permutation set, which can be tens of thousands of
+			// domains for large --dictionary/--tld inputs.
+			resultsCh, errCh := engine.Stream(cmd.Context())
+			write, finish := dnstwist.Results(nil).FormatStream(os.Stdout, options.Format)
+			for result := range resultsCh {
+				if err := write(result); err != nil {
+					return fmt.Errorf("error writing result: %v", err)
+				}
+			}
+			if err := finish(); err != nil {
+				return fmt.Errorf("error finishing output: %v", err)
+			}
+			if err := <-errCh; err != nil {
+				return fmt.Errorf("error streaming results: %v", err)
+			}
 			return nil
 		},
 	}
@@ -59,12 +82,15 @@ func init() {
 	rootCmd.Flags().BoolVarP(&options.All, "all", "a", false, "Print all DNS records instead of the first ones")
 	rootCmd.Flags().BoolVarP(&options.Banners, "banners", "b", false, "Determine HTTP and SMTP service banners")
 	rootCmd.Flags().StringVarP(&options.Dictionary, "dictionary", "", "", "Generate more domains using dictionary file")
-	rootCmd.Flags().StringVarP(&options.Format, "format", "f", "cli", "Output format (cli, csv, json, list)")
+	rootCmd.Flags().BoolVarP(&options.DNSSEC, "dnssec", "", false, "Collect and validate DNSSEC records (DNSKEY/DS/RRSIG/NSEC)")
+	rootCmd.Flags().StringVarP(&options.Format, "format", "f", "cli", "Output format (cli, csv, json, list, ndjson)")
 	rootCmd.Flags().StringVarP(&options.Fuzzers, "fuzzers", "", "", "Fuzzing algorithms to use (comma-separated)")
 	rootCmd.Flags().BoolVarP(&options.GeoIP, "geoip", "g", false, "Perform GeoIP location lookup")
 	rootCmd.Flags().StringVarP(&options.LSH, "lsh", "", "", "Evaluate web page similarity with LSH algorithm (ssdeep, tlsh)")
 	rootCmd.Flags().StringVarP(&options.LSHURL, "lshurl", "", "", "Override URL to fetch the original web page from")
 	rootCmd.Flags().BoolVarP(&options.MXCheck, "mxcheck", "m", false, "Check if MX host can be used to intercept emails")
+	rootCmd.Flags().BoolVarP(&options.EmailAuth, "email-auth", "", false, "Look up and parse SPF/DMARC (and DKIM, with --dkim-selectors) for domains that resolved MX records")
+	rootCmd.Flags().StringSliceVarP(&options.DKIMSelectors, "dkim-selectors", "", []string{}, "DKIM selectors to query (e.g. google,default) when --email-auth is set")
 	rootCmd.Flags().BoolVarP(&options.NSCheck, "nscheck", "x", false, "Check for nameserver records")
 	rootCmd.Flags().StringVarP(&options.Output, "output", "o", "", "Save output to file")
 	rootCmd.Flags().BoolVarP(&options.Registered, "registered", "r", false, "Show only registered domain names")
@@ -76,8 +102,24 @@ func init() {
 	rootCmd.Flags().IntVarP(&options.Threads, "threads", "t", 10, "Number of concurrent threads")
 	rootCmd.Flags().BoolVarP(&options.Whois, "whois", "w", false, "Look up WHOIS database for creation date and registrar")
 	rootCmd.Flags().StringSliceVarP(&options.TLD, "tld", "", []string{}, "Swap TLD for the original domain from files (can be specified multiple times)")
-	rootCmd.Flags().StringVarP(&options.Nameservers, "nameservers", "n", "", "DNS or DoH servers to query (comma-separated)")
+	rootCmd.Flags().StringVarP(&options.Nameservers, "nameservers", "n", "", "DNS servers to query (comma-separated); prefix an entry with udp://, tcp://, tls://, quic://, https://, or sdns:// to pick its transport")
 	rootCmd.Flags().StringVarP(&options.UserAgent, "useragent", "", "Mozilla/5.0 dnstwist", "User-Agent string")
+	rootCmd.Flags().StringVarP(&options.HomoglyphScript, "homoglyph-script", "", "", "Restrict the homoglyph fuzzer to a single Unicode script (cyrillic, greek, armenian, latin)")
+	rootCmd.Flags().BoolVarP(&options.ZoneWalk, "zone-walk", "", false, "Harvest sibling names from a DNSSEC-signed zone via NSEC/NSEC3 (aggressive, opt-in)")
+	rootCmd.Flags().BoolVarP(&options.CT, "ct", "", false, "Query Certificate Transparency logs for registered lookalike domains")
+	rootCmd.Flags().StringSliceVarP(&options.Transports, "transports", "", []string{}, "Ordered list of nameserver endpoints to try, e.g. tls://1.1.1.1:853,https://dns.google/dns-query,sdns://...")
+	rootCmd.Flags().BoolVarP(&options.TransportFallback, "transport-fallback", "", false, "Fall back to the next configured transport/nameserver on timeout or error")
+	rootCmd.Flags().BoolVarP(&options.ForceTCP, "force-tcp", "", false, "Skip UDP entirely and issue every query over TCP")
+	rootCmd.Flags().StringVarP(&options.ResolverStrategy, "resolver-strategy", "", "", "How to combine answers when --nameservers configures several resolvers (first, all, majority)")
+	rootCmd.Flags().IntVarP(&options.ParallelResolvers, "parallel-resolvers", "", 0, "How many resolvers the \"first\" strategy fans a single query out to at once, preferring the healthiest ones (default 2)")
+	rootCmd.Flags().BoolVarP(&options.DisableCache, "disable-cache", "", false, "Disable the in-process DNS answer cache (on by default)")
+	rootCmd.Flags().DurationVarP(&options.CacheTTLCap, "cache-ttl-cap", "", 0, "Cap how long any cached DNS answer is kept, regardless of its reported TTL (default 60s)")
+	rootCmd.Flags().IntVarP(&options.CacheSize, "cache-size", "", 0, "Bound how many DNS answers the cache holds at once, evicting the least recently used entry once exceeded (default 4096)")
+	rootCmd.Flags().BoolVarP(&options.QueryAuthoritative, "query-authoritative", "", false, "Discover and query each domain's authoritative nameserver directly instead of using --nameservers")
+	rootCmd.Flags().StringVarP(&options.PSLFile, "psl-file", "", "", "Override the embedded Public Suffix List with a public_suffix_list.dat file")
+	rootCmd.Flags().StringVarP(&options.QueryStrategy, "query-strategy", "", "", "Which address family to query and use for registration classification: useIP (default, both), useIPv4, useIPv6")
+	rootCmd.Flags().StringVarP(&options.ClientSubnet, "client-subnet", "", "", "Attach an EDNS Client Subnet option (CIDR, e.g. 203.0.113.0/24) to outgoing queries")
+	rootCmd.Flags().IntVarP(&topN, "top", "", 0, "Show only the N most confusable permutations, sorted by --format's Confusability score (0 shows all)")
 
 	// Mark required flags
 	rootCmd.MarkFlagRequired("domain")
@@ -0,0 +1,96 @@
+// Package ct queries Certificate Transparency logs for certificates whose
+// CN/SAN reference a target domain, surfacing real registered lookalikes
+// (e.g. "secure-login-examp1e-support.tld") that permutation-based fuzzers
+// would never think to generate.
+package ct
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the crt.sh JSON search endpoint.
+const DefaultBaseURL = "https://crt.sh/"
+
+// Client queries a CT log search service for candidate FQDNs.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewClient returns a Client that queries crt.sh with a 15 second timeout.
+func NewClient() *Client {
+	return &Client{
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		baseURL:    DefaultBaseURL,
+	}
+}
+
+// entry is the subset of crt.sh's JSON response fields this package reads.
+type entry struct {
+	NameValue  string `json:"name_value"`
+	CommonName string `json:"common_name"`
+}
+
+// Query searches CT logs for certificates whose CN/SAN contain domainPart
+// (the target's registrable name without its TLD, e.g. "example" for
+// "example.com") as a substring, and returns the distinct FQDNs found,
+// lowercased and with any leading wildcard label ("*.") stripped.
+func (c *Client) Query(domainPart string) ([]string, error) {
+	if domainPart == "" {
+		return nil, fmt.Errorf("ct: domainPart must not be empty")
+	}
+
+	reqURL := fmt.Sprintf("%s?q=%s&output=json", c.baseURL, url.QueryEscape("%"+domainPart+"%"))
+
+	resp, err := c.httpClient.Get(reqURL)
+	if err != nil {
+		return nil, fmt.Errorf("ct: query failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ct: unexpected status %d", resp.StatusCode)
+	}
+
+	var entries []entry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("ct: decoding response: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, e := range entries {
+		for _, raw := range strings.Split(e.NameValue, "\n") {
+			for _, name := range normalizeName(raw) {
+				if name != "" && !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+		}
+		for _, name := range normalizeName(e.CommonName) {
+			if name != "" && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names, nil
+}
+
+// normalizeName lowercases a CN/SAN value and strips a leading wildcard
+// label, returning it as a single-element slice (or empty if blank).
+func normalizeName(raw string) []string {
+	name := strings.ToLower(strings.TrimSpace(raw))
+	name = strings.TrimPrefix(name, "*.")
+	if name == "" {
+		return nil
+	}
+	return []string{name}
+}
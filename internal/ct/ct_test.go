@@ -0,0 +1,72 @@
+package ct
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_Query(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("q"); got != "%example%" {
+			t.Errorf("q = %q, want %q", got, "%example%")
+		}
+		json.NewEncoder(w).Encode([]entry{
+			{NameValue: "secure-login-examp1e-support.tld\nexamp1e.tld"},
+			{CommonName: "*.Examp1e.tld"},
+		})
+	}))
+	defer server.Close()
+
+	c := &Client{httpClient: server.Client(), baseURL: server.URL}
+
+	names, err := c.Query("example")
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+
+	want := map[string]bool{
+		"secure-login-examp1e-support.tld": true,
+		"examp1e.tld":                      true,
+	}
+	if len(names) != len(want) {
+		t.Fatalf("names = %v, want %v entries", names, len(want))
+	}
+	for _, n := range names {
+		if !want[n] {
+			t.Errorf("unexpected name %q", n)
+		}
+	}
+}
+
+func TestClient_Query_EmptyDomainPart(t *testing.T) {
+	c := NewClient()
+	if _, err := c.Query(""); err == nil {
+		t.Error("expected error for empty domainPart")
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"Example.TLD", []string{"example.tld"}},
+		{"*.example.tld", []string{"example.tld"}},
+		{"  ", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got := normalizeName(tt.in)
+		if len(got) != len(tt.want) {
+			t.Errorf("normalizeName(%q) = %v, want %v", tt.in, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("normalizeName(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		}
+	}
+}
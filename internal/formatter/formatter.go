@@ -1,9 +1,12 @@
 package formatter
 
 import (
+	"bufio"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
 
 	"github.com/ducksify/godnstwist/internal/fuzzer"
@@ -29,11 +32,61 @@ func (f *Formatter) Format(format string) string {
 		return f.list()
 	case "cli":
 		return f.cli()
+	case "ndjson":
+		return f.ndjson()
 	default:
 		return ""
 	}
 }
 
+func (f *Formatter) ndjson() string {
+	var buf strings.Builder
+	for _, domain := range f.domains {
+		data, err := json.Marshal(domain)
+		if err != nil {
+			continue
+		}
+		buf.Write(data)
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// StreamTo consumes ch, writing each domain to w in format as soon as it
+// arrives instead of buffering the whole set, so callers can pipe a large
+// scan directly to a file or HTTP response. Only "ndjson" flushes after
+// every record; other formats are buffered per-record internally by
+// encoding/json and json.Encoder but still written incrementally. Returns
+// the first write or encode error encountered, if any.
+func (f *Formatter) StreamTo(w io.Writer, ch <-chan *fuzzer.Domain, format string) error {
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+
+	switch format {
+	case "list":
+		for domain := range ch {
+			if _, err := fmt.Fprintln(bw, domain.Domain); err != nil {
+				return err
+			}
+			if err := bw.Flush(); err != nil {
+				return err
+			}
+		}
+		return nil
+	default: // "ndjson" and anything else JSON-shaped
+		enc := json.NewEncoder(bw)
+		for domain := range ch {
+			if err := enc.Encode(domain); err != nil {
+				return err
+			}
+			if err := bw.Flush(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
 func (f *Formatter) json() string {
 	data, err := json.MarshalIndent(f.domains, "", "  ")
 	if err != nil {
@@ -42,39 +95,65 @@ func (f *Formatter) json() string {
 	return string(data)
 }
 
-func (f *Formatter) csv() string {
-	var buf strings.Builder
-	writer := csv.NewWriter(&buf)
+// CSVHeader returns the column header row for the "csv" format, shared by
+// the batch Format("csv") path and callers (like
+// pkg/dnstwist.Results.FormatStream) that write CSV incrementally and need
+// the two to stay in sync.
+func CSVHeader() []string {
+	return []string{"fuzzer", "domain", "punycode", "a_records", "aaaa_records", "mx_records", "ns_records", "dnssec_signed", "dnssec_validated", "confusability"}
+}
 
-	// Write header
-	header := []string{"fuzzer", "domain", "a_records", "mx_records", "ns_records"}
-	writer.Write(header)
+// CSVRecord builds a single "csv" format row for domain, shared by
+// Format("csv") and incremental CSV writers.
+func CSVRecord(domain *fuzzer.Domain) []string {
+	aRecords := ""
+	if a := domain.DNS["A"]; len(a) > 0 {
+		aRecords = strings.Join(a, ";")
+	}
 
-	// Write data
-	for _, domain := range f.domains {
-		aRecords := ""
-		if a := domain.DNS["A"]; len(a) > 0 {
-			aRecords = strings.Join(a, ";")
-		}
+	aaaaRecords := ""
+	if aaaa := domain.DNS["AAAA"]; len(aaaa) > 0 {
+		aaaaRecords = strings.Join(aaaa, ";")
+	}
 
-		mxRecords := ""
-		if mx := domain.DNS["MX"]; len(mx) > 0 {
-			mxRecords = strings.Join(mx, ";")
-		}
+	mxRecords := ""
+	if mx := domain.DNS["MX"]; len(mx) > 0 {
+		mxRecords = strings.Join(mx, ";")
+	}
 
-		nsRecords := ""
-		if ns := domain.DNS["NS"]; len(ns) > 0 {
-			nsRecords = strings.Join(ns, ";")
-		}
+	nsRecords := ""
+	if ns := domain.DNS["NS"]; len(ns) > 0 {
+		nsRecords = strings.Join(ns, ";")
+	}
 
-		record := []string{
-			domain.Fuzzer,
-			domain.Domain,
-			aRecords,
-			mxRecords,
-			nsRecords,
-		}
-		writer.Write(record)
+	dnssecSigned := "false"
+	dnssecValidated := "false"
+	if domain.DNSSEC != nil {
+		dnssecSigned = strconv.FormatBool(domain.DNSSEC.Signed)
+		dnssecValidated = strconv.FormatBool(domain.DNSSEC.Validated)
+	}
+
+	return []string{
+		domain.Fuzzer,
+		domain.Domain,
+		domain.Punycode,
+		aRecords,
+		aaaaRecords,
+		mxRecords,
+		nsRecords,
+		dnssecSigned,
+		dnssecValidated,
+		strconv.Itoa(domain.Confusability),
+	}
+}
+
+func (f *Formatter) csv() string {
+	var buf strings.Builder
+	writer := csv.NewWriter(&buf)
+
+	writer.Write(CSVHeader())
+	for _, domain := range f.domains {
+		writer.Write(CSVRecord(domain))
 	}
 
 	writer.Flush()
@@ -90,6 +169,90 @@ func (f *Formatter) list() string {
 	return buf.String()
 }
 
+// cliInfo builds the "additional information" fields the "cli" format prints
+// after the fuzzer/domain columns (A/AAAA/MX/NS records, GeoIP, banners,
+// DNSSEC posture, confusability), shared by the batch and incremental
+// renderers.
+func cliInfo(domain *fuzzer.Domain) []string {
+	var info []string
+
+	// DNS A records
+	if a := domain.DNS["A"]; len(a) > 0 {
+		info = append(info, strings.Join(a, ";"))
+	}
+
+	// DNS AAAA records
+	if aaaa := domain.DNS["AAAA"]; len(aaaa) > 0 {
+		info = append(info, fmt.Sprintf("AAAA:%s", strings.Join(aaaa, ";")))
+	}
+
+	// DNS MX records
+	if mx := domain.DNS["MX"]; len(mx) > 0 {
+		info = append(info, fmt.Sprintf("MX:%s", strings.Join(mx, ";")))
+	}
+
+	// DNS NS records
+	if ns := domain.DNS["NS"]; len(ns) > 0 {
+		info = append(info, fmt.Sprintf("NS:%s", strings.Join(ns, ";")))
+	}
+
+	// GeoIP
+	if domain.GeoIP != "" {
+		info = append(info, fmt.Sprintf("/%s", domain.GeoIP))
+	}
+
+	// HTTP banner
+	if banner := domain.Banner["http"]; banner != "" {
+		info = append(info, fmt.Sprintf("HTTP:%s", banner))
+	}
+
+	// SMTP banner
+	if banner := domain.Banner["smtp"]; banner != "" {
+		info = append(info, fmt.Sprintf("SMTP:%s", banner))
+	}
+
+	// DNSSEC posture
+	if domain.DNSSEC != nil && domain.DNSSEC.Signed {
+		state := "signed"
+		if domain.DNSSEC.Validated {
+			state = "validated"
+		}
+		info = append(info, fmt.Sprintf("DNSSEC:%s", state))
+	}
+
+	// Email authentication posture (SPF/DMARC)
+	if auth := domain.EmailAuth; auth != nil {
+		if auth.SPF != "" {
+			info = append(info, "SPF:present")
+		}
+		if auth.DMARCPolicy != "" {
+			info = append(info, fmt.Sprintf("DMARC:%s", auth.DMARCPolicy))
+		}
+	}
+
+	// Confusability, skipped for the original domain itself (always 100)
+	if domain.Fuzzer != "original" {
+		info = append(info, fmt.Sprintf("CONF:%d", domain.Confusability))
+	}
+
+	return info
+}
+
+// CLIRecord renders a single domain the way Format("cli") does, except
+// without that format's column-alignment padding, which requires knowing
+// every domain's fuzzer/domain column width up front. Used by incremental
+// callers (like pkg/dnstwist.Results.FormatStream) that print one result at
+// a time and never see the full set.
+func CLIRecord(domain *fuzzer.Domain) string {
+	line := fmt.Sprintf("%s %s", domain.Fuzzer, domain.Domain)
+	if info := cliInfo(domain); len(info) > 0 {
+		line += " " + strings.Join(info, " ")
+	} else {
+		line += " -"
+	}
+	return line
+}
+
 func (f *Formatter) cli() string {
 	var buf strings.Builder
 
@@ -110,41 +273,7 @@ func (f *Formatter) cli() string {
 		// Format fuzzer and domain
 		buf.WriteString(fmt.Sprintf("%-*s %-*s", maxFuzzer+1, domain.Fuzzer, maxDomain+1, domain.Domain))
 
-		// Format additional information
-		var info []string
-
-		// DNS A records
-		if a := domain.DNS["A"]; len(a) > 0 {
-			info = append(info, strings.Join(a, ";"))
-		}
-
-		// DNS MX records
-		if mx := domain.DNS["MX"]; len(mx) > 0 {
-			info = append(info, fmt.Sprintf("MX:%s", strings.Join(mx, ";")))
-		}
-
-		// DNS NS records
-		if ns := domain.DNS["NS"]; len(ns) > 0 {
-			info = append(info, fmt.Sprintf("NS:%s", strings.Join(ns, ";")))
-		}
-
-		// GeoIP
-		if domain.GeoIP != "" {
-			info = append(info, fmt.Sprintf("/%s", domain.GeoIP))
-		}
-
-		// HTTP banner
-		if banner := domain.Banner["http"]; banner != "" {
-			info = append(info, fmt.Sprintf("HTTP:%s", banner))
-		}
-
-		// SMTP banner
-		if banner := domain.Banner["smtp"]; banner != "" {
-			info = append(info, fmt.Sprintf("SMTP:%s", banner))
-		}
-
-		// Add info or dash if no info
-		if len(info) > 0 {
+		if info := cliInfo(domain); len(info) > 0 {
 			buf.WriteString(strings.Join(info, " "))
 		} else {
 			buf.WriteString("-")
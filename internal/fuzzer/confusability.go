@@ -0,0 +1,111 @@
+package fuzzer
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	skeletonOnce sync.Once
+	skeletonMap  map[rune]rune
+)
+
+// loadSkeletonMap lazily inverts loadConfusables' prototype -> variants
+// table into variant -> prototype, the per-rune half of the UTS #39
+// "skeleton" transform: every confusable variant collapses back down to the
+// single Latin (or other source-script) rune it was generated from. Where a
+// variant is reachable from more than one prototype, the first one found
+// wins; the curated table only ever has one prototype per variant in
+// practice.
+func loadSkeletonMap() map[rune]rune {
+	skeletonOnce.Do(func() {
+		skeletonMap = make(map[rune]rune)
+		for proto, variants := range loadConfusables() {
+			for _, v := range variants {
+				if _, ok := skeletonMap[v]; !ok {
+					skeletonMap[v] = proto
+				}
+			}
+		}
+	})
+	return skeletonMap
+}
+
+// skeleton computes the UTS #39 skeleton of s by mapping each rune to the
+// prototype it's confusable with; runes absent from the table pass through
+// unchanged. The curated confusables table only holds precomposed,
+// single-rune mappings (see confusables.go), so there are no combining
+// marks left for an NFD pass to normalize beyond what this substitution
+// already does.
+func skeleton(s string) string {
+	m := loadSkeletonMap()
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if proto, ok := m[r]; ok {
+			r = proto
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// confusability scores how visually confusable domain is with original on a
+// 0-100 scale: identical skeletons (e.g. a pure homoglyph swap) score 100;
+// otherwise the score falls off with the Damerau-Levenshtein distance
+// between the two skeletons, floored at 0, so callers can rank a large
+// permutation set by how dangerous each entry looks at a glance.
+func confusability(domain, original string) int {
+	a, b := skeleton(domain), skeleton(original)
+	if a == b {
+		return 100
+	}
+	score := 99 - damerauLevenshtein(a, b)
+	if score < 0 {
+		score = 0
+	}
+	return score
+}
+
+// damerauLevenshtein computes the optimal-string-alignment edit distance
+// (insertions, deletions, substitutions, and adjacent transpositions of a
+// single rune) between a and b, which is sufficient for the short
+// domain-label skeletons compared here.
+func damerauLevenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	la, lb := len(ra), len(rb)
+
+	d := make([][]int, la+1)
+	for i := range d {
+		d[i] = make([]int, lb+1)
+		d[i][0] = i
+	}
+	for j := 0; j <= lb; j++ {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= la; i++ {
+		for j := 1; j <= lb; j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			min := d[i-1][j] + 1 // deletion
+			if v := d[i][j-1] + 1; v < min {
+				min = v // insertion
+			}
+			if v := d[i-1][j-1] + cost; v < min {
+				min = v // substitution
+			}
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				if v := d[i-2][j-2] + cost; v < min {
+					min = v // transposition
+				}
+			}
+			d[i][j] = min
+		}
+	}
+
+	return d[la][lb]
+}
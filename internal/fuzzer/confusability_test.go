@@ -0,0 +1,63 @@
+package fuzzer
+
+import "testing"
+
+func TestSkeletonMapsConfusablesToPrototype(t *testing.T) {
+	// "а" (U+0430, CYRILLIC SMALL LETTER A) is a registered look-alike for
+	// ASCII "a" in confusables.txt, so both should collapse to the same
+	// skeleton.
+	if got, want := skeleton("аpple"), skeleton("apple"); got != want {
+		t.Errorf("skeleton(%q) = %q, want %q", "аpple", got, want)
+	}
+}
+
+func TestConfusabilityIdenticalSkeletonScoresMax(t *testing.T) {
+	if got := confusability("аpple.com", "apple.com"); got != 100 {
+		t.Errorf("confusability of a pure homoglyph swap = %d, want 100", got)
+	}
+}
+
+func TestConfusabilityFallsOffWithDistance(t *testing.T) {
+	near := confusability("aple.com", "apple.com")
+	far := confusability("xyzzy.net", "apple.com")
+
+	if near <= far {
+		t.Errorf("expected a near-miss (%d) to score higher than an unrelated domain (%d)", near, far)
+	}
+	if near >= 100 {
+		t.Errorf("non-identical skeletons should score below 100, got %d", near)
+	}
+	if far < 0 || far > 99 {
+		t.Errorf("confusability score out of range: %d", far)
+	}
+}
+
+func TestDamerauLevenshteinTransposition(t *testing.T) {
+	// A single adjacent transposition should cost 1, not 2 as plain
+	// Levenshtein would charge for a delete+insert.
+	if got := damerauLevenshtein("ab", "ba"); got != 1 {
+		t.Errorf("damerauLevenshtein(\"ab\", \"ba\") = %d, want 1", got)
+	}
+}
+
+func TestAddDomainPopulatesConfusability(t *testing.T) {
+	f := NewFuzzer("example.com")
+	if f == nil {
+		t.Fatal("Failed to create fuzzer")
+	}
+
+	if err := f.Generate("original"); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	domains := f.Domains()
+	if len(domains) == 0 {
+		t.Fatal("expected at least the original domain")
+	}
+
+	for _, domain := range domains {
+		if domain.Fuzzer == "original" && domain.Confusability != 100 {
+			t.Errorf("original domain Confusability = %d, want 100", domain.Confusability)
+		}
+	}
+}
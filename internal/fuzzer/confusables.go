@@ -0,0 +1,73 @@
+package fuzzer
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed confusables.txt
+var confusablesData []byte
+
+var (
+	confusablesOnce sync.Once
+	confusablesMap  map[rune][]rune
+)
+
+// loadConfusables lazily parses the embedded Unicode Security Mechanisms
+// (UTS #39) confusables table into a map from each source rune to every
+// rune it can be confused with. Only single-source -> single-target entries
+// are kept, matching the "MA" (single character) mapping type.
+func loadConfusables() map[rune][]rune {
+	confusablesOnce.Do(func() {
+		confusablesMap = parseConfusables(confusablesData)
+	})
+	return confusablesMap
+}
+
+func parseConfusables(data []byte) map[rune][]rune {
+	m := make(map[rune][]rune)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if idx := strings.Index(line, "#"); idx != -1 {
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		fields := strings.Split(line, ";")
+		if len(fields) < 2 {
+			continue
+		}
+
+		src := parseCodepoints(fields[0])
+		tgt := parseCodepoints(fields[1])
+		if len(src) != 1 || len(tgt) != 1 {
+			// Skip multi-rune mappings here; those are handled separately
+			// by the curated digraph substitutions in multirune.go.
+			continue
+		}
+
+		m[src[0]] = append(m[src[0]], tgt[0])
+	}
+
+	return m
+}
+
+func parseCodepoints(field string) []rune {
+	var runes []rune
+	for _, tok := range strings.Fields(field) {
+		v, err := strconv.ParseInt(tok, 16, 32)
+		if err != nil {
+			return nil
+		}
+		runes = append(runes, rune(v))
+	}
+	return runes
+}
@@ -0,0 +1,41 @@
+package fuzzer
+
+import "testing"
+
+// FuzzFuzzerGenerate exercises NewFuzzer/Generate with arbitrary domain and
+// fuzzers-list strings, built from TestNewFuzzer and TestFuzzer_Generate's
+// seeds, to catch panics in the permutation generators (addition,
+// bitsquatting, homoglyph, tldSwap, ...) on malformed or adversarial input.
+func FuzzFuzzerGenerate(f *testing.F) {
+	type seed struct {
+		domain  string
+		fuzzers string
+	}
+	seeds := []seed{
+		{"example.com", ""},
+		{"example.com", "addition,bitsquatting,homoglyph"},
+		{"co.uk", "tld-swap"},
+		{"", ""},
+		{"invalid", "addition"},
+		{"xn--e1aybc.xn--p1ai", "homoglyph"},
+		{"\xff\xfe invalid utf-8", "addition,nonexistent-fuzzer"},
+	}
+	for _, s := range seeds {
+		f.Add(s.domain, s.fuzzers)
+	}
+
+	f.Fuzz(func(t *testing.T, domain, fuzzers string) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("NewFuzzer(%q).Generate(%q) panicked: %v", domain, fuzzers, r)
+			}
+		}()
+
+		fz := NewFuzzer(domain)
+		if fz == nil {
+			return
+		}
+		_ = fz.Generate(fuzzers)
+		_ = fz.Domains()
+	})
+}
@@ -7,9 +7,18 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"unicode"
+
+	"golang.org/x/net/idna"
 )
 
-var validFQDNRegex = regexp.MustCompile(`^([a-z0-9-]{1,63}\.)+[a-z]{2,63}$`)
+// validFQDNRegex is a permissive, script-agnostic structural check: each
+// label is one or more letters/digits/hyphens, the final label is alphabetic.
+// It intentionally accepts non-ASCII letters (Cyrillic, Greek, ...) so that
+// homoglyph variants aren't silently dropped before reaching the IDNA and
+// validateFQDNStructure checks below, which enforce the RFC 1035 length and
+// hyphen-placement rules a regex alone can't express.
+var validFQDNRegex = regexp.MustCompile(`^([\p{L}\p{N}-]{1,63}\.)+\p{L}{2,63}$`)
 
 type Domain struct {
 	Fuzzer string
@@ -20,26 +29,152 @@ type Domain struct {
 	Whois  map[string]string
 	LSH    map[string]int
 	PHash  int
+	DNSSEC *DNSSECInfo
+	EDNS   *EDNSInfo
+	// EmailAuth captures the domain's SPF/DMARC/DKIM posture, populated when
+	// Config.EmailAuth is set and the domain resolved MX records.
+	EmailAuth *EmailAuth
+	// Punycode is the ASCII-compatible (A-label) IDNA encoding of Domain. It
+	// is populated for every fuzzer, not just homoglyph, but only differs
+	// from Domain when Domain contains non-ASCII code points.
+	Punycode string
+	// Cyrillic is true when Domain contains a Cyrillic code point.
+	Cyrillic bool
+	// ResolvedBy identifies the resolver(s) (comma-separated when the "all"
+	// or "majority" strategy combined several) that produced the A record
+	// answer, when the scanner is configured with more than one resolver.
+	ResolvedBy string
+	// Confusability scores how visually confusable Domain is with the
+	// original domain on a 0-100 scale, per the UTS #39 skeleton algorithm.
+	// 100 means the two are indistinguishable at a glance (e.g. a pure
+	// homoglyph swap); lower scores mean the permutation differs enough
+	// that a careless reader would likely notice.
+	Confusability int
+}
+
+// EDNSInfo captures the response-side EDNS0 options a nameserver returned,
+// such as the NSID it identified itself with or an RFC 8914 extended error.
+type EDNSInfo struct {
+	// NSID is the nameserver identifier returned via the NSID EDNS0 option.
+	NSID string
+	// Padding is the number of padding bytes returned via RFC 7830.
+	Padding int
+	// ExtendedErrorCode is the RFC 8914 Extended DNS Error info-code, or -1 if none.
+	ExtendedErrorCode int
+	// ExtendedErrorText is the optional human-readable extra text for the error.
+	ExtendedErrorText string
+}
+
+// Mechanism is a single parsed SPF term (RFC 7208 section 4.6.1): an
+// optional qualifier ("+", "-", "~", "?"; "" means the default "+"), a
+// mechanism/modifier type ("include", "a", "mx", "ip4", "ip6", "redirect",
+// "all", "exists", "ptr", ...), and its value, if any (e.g. the domain after
+// "include:").
+type Mechanism struct {
+	Qualifier string
+	Type      string
+	Value     string
+}
+
+// EmailAuth captures a domain's SPF/DMARC/DKIM posture, gathered to triage
+// how exposed a lookalike domain is for outbound mail spoofing (a typosquat
+// with a permissive or absent SPF/DMARC policy is trivially usable to send
+// mail that appears to come from it).
+type EmailAuth struct {
+	// SPF is the apex's raw "v=spf1 ..." TXT record, if any.
+	SPF string
+	// SPFMechanisms is SPF parsed into its individual terms, in order.
+	SPFMechanisms []Mechanism
+	// SPFResolved maps each "a"/"mx" mechanism's explicit value to the A
+	// records it resolved to, so a cousin-domain's mail infrastructure can
+	// be compared against the real domain's.
+	SPFResolved map[string][]string
+	// DMARCPolicy is the "p=" tag from "_dmarc.<domain>"'s TXT record
+	// ("none", "quarantine", or "reject"), the policy applied to mail
+	// claiming to be From: the domain itself.
+	DMARCPolicy string
+	// DMARCSubdomainPolicy is the "sp=" tag, overriding DMARCPolicy for
+	// subdomains. Empty means DMARCPolicy applies to subdomains too.
+	DMARCSubdomainPolicy string
+	// DMARCPercent is the "pct=" tag: the percentage of failing mail the
+	// policy applies to. Defaults to 100 per RFC 7489 when absent (left 0
+	// here; absence is distinguishable via DMARCPolicy == "").
+	DMARCPercent int
+	// DMARCReportURI is the "rua=" tag's aggregate-report destination.
+	DMARCReportURI string
+	// DKIMSelectors maps each queried selector (from Config.DKIMSelectors)
+	// to its "<selector>._domainkey.<domain>" TXT record, for selectors
+	// that resolved one.
+	DKIMSelectors map[string]string
+}
+
+// DNSSECInfo captures the DNSSEC posture observed while resolving a domain.
+type DNSSECInfo struct {
+	// Signed is true when the zone returned RRSIG/DNSKEY material.
+	Signed bool
+	// Validated is true when the signature chain could be verified locally.
+	Validated bool
+	// Algorithms lists the DNSKEY/RRSIG algorithm numbers seen (RFC 8624).
+	Algorithms []uint8
+	// KeyTags lists the DNSKEY key tags seen in the response.
+	KeyTags []uint16
 }
 
 type Fuzzer struct {
-	subdomainPart string
-	domain        string
-	tld           string
-	domains       []*Domain
-	mu            sync.RWMutex
-	tldFile       string // Add TLD file path
+	subdomainPart   string
+	domain          string
+	tld             string
+	domains         []*Domain
+	mu              sync.RWMutex
+	tldFile         string // Add TLD file path
+	homoglyphScript string
 }
 
+// NewFuzzer creates a Fuzzer for domain, splitting it into a subdomain, a
+// registrable label, and a public suffix using the eTLD+1 computed from the
+// embedded golang.org/x/net/publicsuffix list, e.g. "www.example.co.uk"
+// splits into ("www", "example", "co.uk") rather than naively treating "uk"
+// as the TLD. Returns nil if domain is itself a public suffix (like "co.uk")
+// or otherwise has no registrable eTLD+1.
 func NewFuzzer(domain string) *Fuzzer {
-	parts := strings.Split(domain, ".")
-	if len(parts) < 2 {
+	return newFuzzer(domain, embeddedPSL{})
+}
+
+// NewFuzzerWithPSLFile behaves like NewFuzzer but resolves the public suffix
+// against a custom Public Suffix List file (the format published at
+// https://publicsuffix.org/list/public_suffix_list.dat) instead of the
+// embedded table, so callers can pin or update the list independently of
+// this binary's release. Returns nil if pslFile can't be read or domain is
+// invalid.
+func NewFuzzerWithPSLFile(domain, pslFile string) *Fuzzer {
+	list, err := loadPSLFile(pslFile)
+	if err != nil {
 		return nil
 	}
+	return newFuzzer(domain, list)
+}
 
-	tld := parts[len(parts)-1]
-	domainPart := parts[len(parts)-2]
-	subdomain := strings.Join(parts[:len(parts)-2], ".")
+func newFuzzer(domain string, psl pslSource) *Fuzzer {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	if domain == "" || !strings.Contains(domain, ".") {
+		return nil
+	}
+	if psl.isPublicSuffix(domain) {
+		return nil
+	}
+
+	registrable, err := psl.effectiveTLDPlusOne(domain)
+	if err != nil {
+		return nil
+	}
+
+	dot := strings.IndexByte(registrable, '.')
+	if dot == -1 {
+		return nil
+	}
+	domainPart, tld := registrable[:dot], registrable[dot+1:]
+
+	subdomain := strings.TrimSuffix(strings.TrimSuffix(domain, registrable), ".")
 
 	return &Fuzzer{
 		subdomainPart: subdomain,
@@ -54,9 +189,22 @@ func (f *Fuzzer) SetTLDFile(tldFile string) {
 	f.tldFile = tldFile
 }
 
+// SetHomoglyphScript restricts the homoglyph fuzzer to confusables from a
+// single Unicode script (e.g. "cyrillic", "greek", "armenian"). An empty
+// string, the default, considers confusables from every script.
+func (f *Fuzzer) SetHomoglyphScript(script string) {
+	f.homoglyphScript = script
+}
+
+// originalFQDN returns the fully-qualified domain the Fuzzer was built
+// from, e.g. "example.com".
+func (f *Fuzzer) originalFQDN() string {
+	return fmt.Sprintf("%s.%s", f.domain, f.tld)
+}
+
 func (f *Fuzzer) Generate(fuzzers string) error {
 	// Add original domain
-	f.addDomain("original", fmt.Sprintf("%s.%s", f.domain, f.tld))
+	f.addDomain("original", f.originalFQDN())
 
 	// Parse fuzzers
 	fuzzerList := strings.Split(fuzzers, ",")
@@ -103,24 +251,148 @@ func (f *Fuzzer) Generate(fuzzers string) error {
 	return nil
 }
 
+// AddExternal admits domain candidates discovered by an external source
+// (e.g. Certificate Transparency log ingestion) under the given fuzzer
+// label, subject to the same FQDN validation as permutation-generated
+// domains.
+func (f *Fuzzer) AddExternal(fuzzer string, domains []string) {
+	for _, domain := range domains {
+		f.addDomain(fuzzer, strings.ToLower(strings.TrimSpace(domain)))
+	}
+}
+
+// addDomain validates a generated permutation and, if it passes, records it.
+// Validation runs in three stages: validFQDNRegex's structural shape, the
+// RFC 1035 length/hyphen rules in validateFQDNStructure (which also rejects
+// whole-script-confusable labels unless fuzzer is "homoglyph", the one fuzzer
+// that intentionally mixes scripts), and an IDNA2008/UTS #46 round-trip
+// through idna.Lookup.ToASCII and idna.ToUnicode. The round-trip guards
+// against mangled multi-byte variants: if decoding the Punycode A-label
+// doesn't reproduce the input exactly, the variant isn't a valid IDN and is
+// dropped. Domain and Punycode are populated for every fuzzer this way, not
+// just homoglyph.
 func (f *Fuzzer) addDomain(fuzzer, domain string) {
+	domain = strings.ToLower(domain)
+
 	if !validFQDNRegex.MatchString(domain) {
 		return
 	}
+	if !validateFQDNStructure(domain, fuzzer == "homoglyph") {
+		return
+	}
+
+	ascii, err := idna.Lookup.ToASCII(domain)
+	if err != nil {
+		return
+	}
+	unicodeForm, err := idna.ToUnicode(ascii)
+	if err != nil || unicodeForm != domain {
+		return
+	}
 
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
 	f.domains = append(f.domains, &Domain{
-		Fuzzer: fuzzer,
-		Domain: domain,
-		DNS:    make(map[string][]string),
-		Banner: make(map[string]string),
-		Whois:  make(map[string]string),
-		LSH:    make(map[string]int),
+		Fuzzer:        fuzzer,
+		Domain:        unicodeForm,
+		DNS:           make(map[string][]string),
+		Banner:        make(map[string]string),
+		Whois:         make(map[string]string),
+		LSH:           make(map[string]int),
+		Punycode:      ascii,
+		Cyrillic:      containsCyrillic(domain),
+		Confusability: confusability(unicodeForm, f.originalFQDN()),
 	})
 }
 
+// validateFQDNStructure enforces the RFC 1035 length limits and hyphen
+// placement that validFQDNRegex's quantifiers can't express on their own:
+// each label is 1-63 runes with no leading or trailing hyphen, and the whole
+// name is at most 253 runes. Unless allowMixedScript is set, it also rejects
+// labels mixing letters from more than one Unicode script (e.g. Latin "o"
+// next to Cyrillic "о") since that combination is only ever intentional for
+// the homoglyph fuzzer's single-rune confusable swaps.
+func validateFQDNStructure(domain string, allowMixedScript bool) bool {
+	if len(domain) == 0 || len([]rune(domain)) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(domain, ".") {
+		runes := []rune(label)
+		if len(runes) == 0 || len(runes) > 63 {
+			return false
+		}
+		if runes[0] == '-' || runes[len(runes)-1] == '-' {
+			return false
+		}
+		if !allowMixedScript && mixesScripts(runes) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mixesScripts reports whether label's letters belong to more than one
+// Unicode script. Non-letters (digits, hyphens) are ignored since they're
+// shared by every script and would otherwise look like a false mix.
+func mixesScripts(label []rune) bool {
+	seen := ""
+	for _, r := range label {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		script := runeScript(r)
+		if script == "" {
+			continue
+		}
+		if seen == "" {
+			seen = script
+		} else if seen != script {
+			return true
+		}
+	}
+	return false
+}
+
+// runeScript returns the name of the Unicode script r belongs to, ignoring
+// the Common and Inherited pseudo-scripts shared across scripts, or "" if
+// none matches.
+func runeScript(r rune) string {
+	for name, table := range unicode.Scripts {
+		if name == "Common" || name == "Inherited" {
+			continue
+		}
+		if unicode.Is(table, r) {
+			return name
+		}
+	}
+	return ""
+}
+
+// containsCyrillic reports whether s contains at least one Cyrillic code
+// point.
+func containsCyrillic(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsNonASCII reports whether s contains at least one code point
+// outside the ASCII range.
+func containsNonASCII(s string) bool {
+	for _, r := range s {
+		if r > 127 {
+			return true
+		}
+	}
+	return false
+}
+
 func (f *Fuzzer) addition() {
 	// Add characters to domain
 	for i := 'a'; i <= 'z'; i++ {
@@ -145,41 +417,36 @@ func (f *Fuzzer) bitsquatting() {
 	}
 }
 
+// homoglyph replaces each character of the domain with every Unicode
+// confusable registered in the embedded UTS #39 confusables table, plus a
+// handful of curated ASCII digraph substitutions (e.g. "rn" -> "m"). Single
+// and multi-rune substitutions are generated separately because only the
+// former can produce a domain containing non-ASCII code points.
 func (f *Fuzzer) homoglyph() {
-	// Replace characters with similar looking ones
-	homoglyphs := map[rune][]rune{
-		'a': {'а', 'α', 'а'},
-		'b': {'ь', 'в'},
-		'c': {'с', 'ç'},
-		'd': {'ԁ', 'd'},
-		'e': {'е', 'е'},
-		'g': {'ɡ', 'g'},
-		'h': {'һ', 'h'},
-		'i': {'і', 'i'},
-		'j': {'ј', 'j'},
-		'k': {'к', 'k'},
-		'l': {'ӏ', 'l'},
-		'm': {'м', 'm'},
-		'n': {'п', 'n'},
-		'o': {'о', 'ο'},
-		'p': {'р', 'p'},
-		'q': {'ԛ', 'q'},
-		's': {'ѕ', 's'},
-		't': {'т', 't'},
-		'u': {'υ', 'u'},
-		'v': {'ѵ', 'v'},
-		'w': {'ԝ', 'w'},
-		'x': {'х', 'x'},
-		'y': {'у', 'y'},
-		'z': {'z', 'z'},
+	confusables := loadConfusables()
+
+	runes := []rune(f.domain)
+	for i, c := range runes {
+		for _, r := range confusables[c] {
+			if !matchesScript(r, f.homoglyphScript) {
+				continue
+			}
+			newDomain := string(runes[:i]) + string(r) + string(runes[i+1:])
+			f.addDomain("homoglyph", fmt.Sprintf("%s.%s", newDomain, f.tld))
+		}
 	}
 
-	for i, c := range f.domain {
-		if replacements, ok := homoglyphs[c]; ok {
-			for _, r := range replacements {
-				newDomain := f.domain[:i] + string(r) + f.domain[i+1:]
-				f.addDomain("homoglyph", fmt.Sprintf("%s.%s", newDomain, f.tld))
+	for seq, replacement := range multiRuneSubstitutions {
+		idx := 0
+		for {
+			pos := strings.Index(f.domain[idx:], seq)
+			if pos == -1 {
+				break
 			}
+			pos += idx
+			newDomain := f.domain[:pos] + replacement + f.domain[pos+len(seq):]
+			f.addDomain("homoglyph", fmt.Sprintf("%s.%s", newDomain, f.tld))
+			idx = pos + 1
 		}
 	}
 }
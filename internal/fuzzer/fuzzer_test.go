@@ -30,6 +30,21 @@ func TestNewFuzzer(t *testing.T) {
 			domain:  "",
 			wantErr: true,
 		},
+		{
+			name:    "multi-label public suffix is not a registrable domain",
+			domain:  "co.uk",
+			wantErr: true,
+		},
+		{
+			name:    "single-label public suffix is not a registrable domain",
+			domain:  "com",
+			wantErr: true,
+		},
+		{
+			name:    "multi-label suffix with registrable label",
+			domain:  "example.co.uk",
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -415,3 +430,56 @@ func TestRegexWithCyrillic(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateFQDNStructure(t *testing.T) {
+	tests := []struct {
+		name             string
+		domain           string
+		allowMixedScript bool
+		expected         bool
+	}{
+		{"valid ascii", "example.com", false, true},
+		{"label too long", strings.Repeat("a", 64) + ".com", false, false},
+		{"total too long", strings.Repeat("a.", 127) + "com", false, false},
+		{"leading hyphen", "-example.com", false, false},
+		{"trailing hyphen", "example-.com", false, false},
+		{"mixed script rejected", "gоogle.com", false, false}, // Latin + Cyrillic 'о'
+		{"mixed script allowed for homoglyph", "gоogle.com", true, true},
+		{"single script with digits", "example1.com", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validateFQDNStructure(tt.domain, tt.allowMixedScript)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestAddDomainPopulatesPunycodeForEveryFuzzer(t *testing.T) {
+	f := NewFuzzer("example.com")
+	assert.NotNil(t, f)
+
+	f.addDomain("addition", "examplea.com")
+
+	var found *Domain
+	for _, d := range f.Domains() {
+		if d.Domain == "examplea.com" {
+			found = d
+			break
+		}
+	}
+
+	if assert.NotNil(t, found, "ASCII domain from a non-homoglyph fuzzer should be recorded") {
+		assert.Equal(t, "examplea.com", found.Punycode, "Punycode should be populated even for ASCII-only domains")
+	}
+}
+
+func TestAddDomainRejectsInvalidLabelLength(t *testing.T) {
+	f := NewFuzzer("example.com")
+	assert.NotNil(t, f)
+
+	before := len(f.Domains())
+	f.addDomain("addition", strings.Repeat("a", 64)+".com")
+	assert.Equal(t, before, len(f.Domains()), "a 64-rune label should be rejected")
+}
@@ -0,0 +1,14 @@
+package fuzzer
+
+// multiRuneSubstitutions lists common multi-character sequences that are
+// visually confusable with a single character in most fonts (e.g. "rn"
+// rendering almost identically to "m"). These stay within ASCII, unlike the
+// single-rune confusables table, so the result always produces a valid
+// domain label on its own.
+var multiRuneSubstitutions = map[string]string{
+	"rn": "m",
+	"cl": "d",
+	"vv": "w",
+	"nn": "m",
+	"ii": "u",
+}
@@ -0,0 +1,137 @@
+package fuzzer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// pslSource resolves the registrable eTLD+1 and public-suffix status of a
+// domain name. embeddedPSL defers to golang.org/x/net/publicsuffix's
+// compiled-in snapshot of the list; pslList parses a caller-supplied PSL
+// file to override it (see Options.PSLFile in the dnstwist package).
+type pslSource interface {
+	// effectiveTLDPlusOne returns the registrable domain (public suffix plus
+	// one more label), e.g. "example.co.uk" for "www.example.co.uk". It
+	// errors if domain is itself a public suffix or malformed.
+	effectiveTLDPlusOne(domain string) (string, error)
+	// isPublicSuffix reports whether domain is, in its entirety, a public
+	// suffix (e.g. "co.uk"), and so has no registrable label of its own.
+	isPublicSuffix(domain string) bool
+}
+
+// embeddedPSL is the default pslSource, backed by the table golang.org/x/net
+// generates from the upstream list at https://publicsuffix.org/list/.
+type embeddedPSL struct{}
+
+func (embeddedPSL) effectiveTLDPlusOne(domain string) (string, error) {
+	return publicsuffix.EffectiveTLDPlusOne(domain)
+}
+
+func (embeddedPSL) isPublicSuffix(domain string) bool {
+	suffix, _ := publicsuffix.PublicSuffix(domain)
+	return suffix == domain
+}
+
+// pslList is a minimal Public Suffix List matcher loaded from a file in the
+// format published at https://publicsuffix.org/list/public_suffix_list.dat:
+// one rule per line, "//" comments, "*." wildcard rules, and "!" exception
+// rules.
+type pslList struct {
+	rules      map[string]bool
+	wildcards  map[string]bool
+	exceptions map[string]bool
+}
+
+// loadPSLFile parses a Public Suffix List file.
+func loadPSLFile(path string) (*pslList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	list := &pslList{
+		rules:      make(map[string]bool),
+		wildcards:  make(map[string]bool),
+		exceptions: make(map[string]bool),
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "!"):
+			list.exceptions[strings.ToLower(line[1:])] = true
+		case strings.HasPrefix(line, "*."):
+			list.wildcards[strings.ToLower(line[2:])] = true
+		default:
+			list.rules[strings.ToLower(line)] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// publicSuffix applies the standard PSL longest-match algorithm: an
+// exception rule always wins (its match minus the leftmost label is the
+// suffix), otherwise the longest matching plain or wildcard rule wins, and
+// the implicit "*" rule (the domain's last label alone) applies when nothing
+// else matches.
+func (list *pslList) publicSuffix(domain string) string {
+	labels := strings.Split(strings.ToLower(domain), ".")
+	n := len(labels)
+
+	for i := 0; i < n; i++ {
+		if list.exceptions[strings.Join(labels[i:], ".")] {
+			return strings.Join(labels[i+1:], ".")
+		}
+	}
+
+	bestLabels := 1
+	best := labels[n-1]
+
+	for i := 0; i < n; i++ {
+		suffix := strings.Join(labels[i:], ".")
+		count := n - i
+
+		if list.rules[suffix] && count > bestLabels {
+			bestLabels, best = count, suffix
+		}
+		if i > 0 && list.wildcards[suffix] && count+1 > bestLabels {
+			bestLabels, best = count+1, strings.Join(labels[i-1:], ".")
+		}
+	}
+
+	return best
+}
+
+func (list *pslList) isPublicSuffix(domain string) bool {
+	return list.publicSuffix(strings.ToLower(domain)) == strings.ToLower(domain)
+}
+
+func (list *pslList) effectiveTLDPlusOne(domain string) (string, error) {
+	domain = strings.ToLower(domain)
+	suffix := list.publicSuffix(domain)
+
+	if len(domain) <= len(suffix) {
+		return "", fmt.Errorf("fuzzer: %q is a public suffix", domain)
+	}
+
+	i := len(domain) - len(suffix) - 1
+	if domain[i] != '.' {
+		return "", fmt.Errorf("fuzzer: invalid public suffix %q for domain %q", suffix, domain)
+	}
+
+	return domain[1+strings.LastIndexByte(domain[:i], '.'):], nil
+}
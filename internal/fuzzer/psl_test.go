@@ -0,0 +1,110 @@
+package fuzzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestPSL(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "public_suffix_list.dat")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write test PSL file: %v", err)
+	}
+	return path
+}
+
+func TestLoadPSLFile(t *testing.T) {
+	path := writeTestPSL(t, `
+// comment
+com
+co.uk
+*.ck
+!www.ck
+`)
+
+	list, err := loadPSLFile(path)
+	if err != nil {
+		t.Fatalf("loadPSLFile() error = %v", err)
+	}
+
+	assert.True(t, list.rules["com"])
+	assert.True(t, list.rules["co.uk"])
+	assert.True(t, list.wildcards["ck"])
+	assert.True(t, list.exceptions["www.ck"])
+}
+
+func TestLoadPSLFileMissing(t *testing.T) {
+	_, err := loadPSLFile(filepath.Join(t.TempDir(), "missing.dat"))
+	assert.Error(t, err)
+}
+
+func TestPSLListPublicSuffix(t *testing.T) {
+	path := writeTestPSL(t, `
+com
+co.uk
+*.ck
+!www.ck
+`)
+	list, err := loadPSLFile(path)
+	if err != nil {
+		t.Fatalf("loadPSLFile() error = %v", err)
+	}
+
+	tests := []struct {
+		domain   string
+		expected string
+	}{
+		{"example.com", "com"},
+		{"example.co.uk", "co.uk"},
+		{"foo.ck", "foo.ck"},   // wildcard match
+		{"www.ck", "ck"},       // exception overrides wildcard
+		{"example.org", "org"}, // no rule: implicit "*" rule (last label)
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, list.publicSuffix(tt.domain), "publicSuffix(%q)", tt.domain)
+	}
+}
+
+func TestPSLListEffectiveTLDPlusOne(t *testing.T) {
+	path := writeTestPSL(t, `
+com
+co.uk
+`)
+	list, err := loadPSLFile(path)
+	if err != nil {
+		t.Fatalf("loadPSLFile() error = %v", err)
+	}
+
+	etld1, err := list.effectiveTLDPlusOne("www.example.co.uk")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.co.uk", etld1)
+
+	_, err = list.effectiveTLDPlusOne("co.uk")
+	assert.Error(t, err, "a bare public suffix has no eTLD+1")
+}
+
+func TestNewFuzzerWithPSLFile(t *testing.T) {
+	path := writeTestPSL(t, "co.uk\n")
+
+	f := NewFuzzerWithPSLFile("example.co.uk", path)
+	if assert.NotNil(t, f) {
+		assert.Equal(t, "example", f.domain)
+		assert.Equal(t, "co.uk", f.tld)
+	}
+
+	assert.Nil(t, NewFuzzerWithPSLFile("co.uk", path), "a bare public suffix should be rejected")
+	assert.Nil(t, NewFuzzerWithPSLFile("example.co.uk", filepath.Join(t.TempDir(), "missing.dat")), "an unreadable PSL file should be rejected")
+}
+
+func TestNewFuzzerMultiLabelSuffix(t *testing.T) {
+	f := NewFuzzer("www.example.co.uk")
+	if assert.NotNil(t, f) {
+		assert.Equal(t, "example", f.domain)
+		assert.Equal(t, "co.uk", f.tld)
+	}
+}
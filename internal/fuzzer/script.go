@@ -0,0 +1,26 @@
+package fuzzer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// matchesScript reports whether r belongs to the named Unicode script.
+// An empty or unrecognized name matches everything, so --homoglyph-script
+// is a pure opt-in filter.
+func matchesScript(r rune, script string) bool {
+	switch strings.ToLower(script) {
+	case "":
+		return true
+	case "cyrillic":
+		return unicode.Is(unicode.Cyrillic, r)
+	case "greek":
+		return unicode.Is(unicode.Greek, r)
+	case "armenian":
+		return unicode.Is(unicode.Armenian, r)
+	case "latin", "latin-extended":
+		return unicode.Is(unicode.Latin, r)
+	default:
+		return true
+	}
+}
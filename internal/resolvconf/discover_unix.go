@@ -0,0 +1,25 @@
+//go:build !windows
+
+package resolvconf
+
+import "os"
+
+// DefaultPath is the conventional location of the system resolver
+// configuration on Unix-like systems. Var rather than const so tests can
+// point it at a fixture file.
+var DefaultPath = "/etc/resolv.conf"
+
+// DiscoverNameservers returns the nameservers configured in DefaultPath, in
+// the order resolv.conf lists them. Returns nil, without an error, if
+// DefaultPath doesn't exist (e.g. a system using only an NSS module), so
+// callers can fall back to a hardcoded default.
+func DiscoverNameservers() ([]string, error) {
+	conf, err := os.ReadFile(DefaultPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return GetNameservers(conf), nil
+}
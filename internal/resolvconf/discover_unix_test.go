@@ -0,0 +1,43 @@
+//go:build !windows
+
+package resolvconf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiscoverNameservers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "resolv.conf")
+	if err := os.WriteFile(path, []byte("nameserver 127.0.0.53\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	old := DefaultPath
+	DefaultPath = path
+	defer func() { DefaultPath = old }()
+
+	servers, err := DiscoverNameservers()
+	if err != nil {
+		t.Fatalf("DiscoverNameservers() error = %v", err)
+	}
+	if len(servers) != 1 || servers[0] != "127.0.0.53" {
+		t.Errorf("DiscoverNameservers() = %v, want [127.0.0.53]", servers)
+	}
+}
+
+func TestDiscoverNameservers_MissingFile(t *testing.T) {
+	old := DefaultPath
+	DefaultPath = filepath.Join(t.TempDir(), "does-not-exist.conf")
+	defer func() { DefaultPath = old }()
+
+	servers, err := DiscoverNameservers()
+	if err != nil {
+		t.Fatalf("DiscoverNameservers() error = %v, want nil for a missing file", err)
+	}
+	if servers != nil {
+		t.Errorf("DiscoverNameservers() = %v, want nil", servers)
+	}
+}
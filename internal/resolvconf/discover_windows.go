@@ -0,0 +1,70 @@
+//go:build windows
+
+package resolvconf
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// DiscoverNameservers returns the DNS server addresses configured on the
+// machine's network adapters via the iphlpapi GetAdaptersAddresses call,
+// since Windows has no /etc/resolv.conf equivalent.
+func DiscoverNameservers() ([]string, error) {
+	aas, err := adapterAddresses()
+	if err != nil {
+		return nil, err
+	}
+
+	var servers []string
+	seen := make(map[string]bool)
+	for _, aa := range aas {
+		for dns := aa.FirstDnsServerAddress; dns != nil; dns = dns.Next {
+			ip := dns.Address.IP()
+			if ip == nil {
+				continue
+			}
+			addr := ip.String()
+			if seen[addr] {
+				continue
+			}
+			seen[addr] = true
+			servers = append(servers, addr)
+		}
+	}
+
+	return servers, nil
+}
+
+// adapterAddresses enumerates the machine's network adapters via
+// GetAdaptersAddresses, growing the supplied buffer until it's large enough,
+// the same pattern the Go standard library's net package uses internally.
+func adapterAddresses() ([]*windows.IpAdapterAddresses, error) {
+	var b []byte
+	l := uint32(15000) // recommended initial size
+	for {
+		b = make([]byte, l)
+		err := windows.GetAdaptersAddresses(syscall.AF_UNSPEC, windows.GAA_FLAG_INCLUDE_PREFIX, 0, (*windows.IpAdapterAddresses)(unsafe.Pointer(&b[0])), &l)
+		if err == nil {
+			if l == 0 {
+				return nil, nil
+			}
+			break
+		}
+		if err != syscall.ERROR_BUFFER_OVERFLOW {
+			return nil, fmt.Errorf("GetAdaptersAddresses: %w", err)
+		}
+		if l <= uint32(len(b)) {
+			return nil, fmt.Errorf("GetAdaptersAddresses: %w", err)
+		}
+	}
+
+	var aas []*windows.IpAdapterAddresses
+	for aa := (*windows.IpAdapterAddresses)(unsafe.Pointer(&b[0])); aa != nil; aa = aa.Next {
+		aas = append(aas, aa)
+	}
+	return aas, nil
+}
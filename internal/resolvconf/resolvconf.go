@@ -0,0 +1,58 @@
+// Package resolvconf parses /etc/resolv.conf (and, on Windows, the system's
+// configured network adapters) to discover the recursive resolvers and
+// search domains the local machine is already configured to use, so Scanner
+// can query them instead of falling back to a hardcoded public resolver.
+package resolvconf
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+// GetNameservers parses the "nameserver <addr>" lines out of a resolv.conf
+// file's contents, stripping "#"/";" comments and surrounding whitespace and
+// preserving the order the file lists them in. Malformed or duplicate
+// "options"/"search" lines and blank lines are ignored; a bare address with
+// no "nameserver" keyword is not a nameserver.
+func GetNameservers(conf []byte) []string {
+	var servers []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(conf))
+	for scanner.Scan() {
+		fields := strings.Fields(stripComment(scanner.Text()))
+		if len(fields) != 2 || fields[0] != "nameserver" {
+			continue
+		}
+		servers = append(servers, fields[1])
+	}
+
+	return servers
+}
+
+// GetSearchDomains parses the "search <domain>..." directive out of a
+// resolv.conf file's contents. Per resolv.conf(5), a later "search" line
+// replaces an earlier one rather than appending to it.
+func GetSearchDomains(conf []byte) []string {
+	var domains []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(conf))
+	for scanner.Scan() {
+		fields := strings.Fields(stripComment(scanner.Text()))
+		if len(fields) < 2 || fields[0] != "search" {
+			continue
+		}
+		domains = fields[1:]
+	}
+
+	return domains
+}
+
+// stripComment truncates line at the first unescaped "#" or ";", per
+// resolv.conf(5)'s comment syntax.
+func stripComment(line string) string {
+	if i := strings.IndexAny(line, "#;"); i >= 0 {
+		line = line[:i]
+	}
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,97 @@
+package resolvconf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetNameservers(t *testing.T) {
+	tests := []struct {
+		name string
+		conf string
+		want []string
+	}{
+		{
+			name: "simple",
+			conf: "nameserver 8.8.8.8\nnameserver 1.1.1.1\n",
+			want: []string{"8.8.8.8", "1.1.1.1"},
+		},
+		{
+			name: "preserves order",
+			conf: "nameserver 1.1.1.1\nnameserver 8.8.8.8\n",
+			want: []string{"1.1.1.1", "8.8.8.8"},
+		},
+		{
+			name: "ignores comments and blank lines",
+			conf: "# generated by NetworkManager\n\n nameserver 8.8.8.8 ; trailing comment\n;nameserver 9.9.9.9\n",
+			want: []string{"8.8.8.8"},
+		},
+		{
+			name: "ignores unrelated directives",
+			conf: "domain example.com\nsearch example.com corp.example.com\noptions ndots:2 timeout:1 attempts:3\nnameserver 10.0.0.1\n",
+			want: []string{"10.0.0.1"},
+		},
+		{
+			name: "ipv6 nameserver",
+			conf: "nameserver ::1\n",
+			want: []string{"::1"},
+		},
+		{
+			name: "leading/trailing whitespace tolerated",
+			conf: "   nameserver\t8.8.4.4   \n",
+			want: []string{"8.8.4.4"},
+		},
+		{
+			name: "empty file",
+			conf: "",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetNameservers([]byte(tt.conf))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetNameservers(%q) = %v, want %v", tt.conf, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSearchDomains(t *testing.T) {
+	tests := []struct {
+		name string
+		conf string
+		want []string
+	}{
+		{
+			name: "simple",
+			conf: "search example.com corp.example.com\n",
+			want: []string{"example.com", "corp.example.com"},
+		},
+		{
+			name: "later search line wins",
+			conf: "search first.example.com\nnameserver 8.8.8.8\nsearch second.example.com\n",
+			want: []string{"second.example.com"},
+		},
+		{
+			name: "none configured",
+			conf: "nameserver 8.8.8.8\n",
+			want: nil,
+		},
+		{
+			name: "comment stripped",
+			conf: "search example.com # the corporate domain\n",
+			want: []string{"example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetSearchDomains([]byte(tt.conf))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetSearchDomains(%q) = %v, want %v", tt.conf, got, tt.want)
+			}
+		})
+	}
+}
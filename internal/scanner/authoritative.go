@@ -0,0 +1,178 @@
+package scanner
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// findAuthoritativeNS walks up fqdn's label hierarchy, issuing SOA queries
+// against the configured recursive resolver until one resolves (either as a
+// direct Answer, for the zone apex, or as an Authority-section referral to
+// an ancestor zone), then resolves that zone's NS set to dialable "ip:53"
+// addresses. This is the same zone-cut-discovery technique ACME DNS-01
+// clients use to find the authority responsible for a name.
+//
+// The walk is bounded by fqdn's label count, so it terminates instead of
+// looping forever if the SOA search bottoms out without ever finding one
+// (e.g. a broken delegation with no SOA anywhere up to the TLD).
+func (s *Scanner) findAuthoritativeNS(fqdn string) ([]string, error) {
+	labels := dns.SplitDomainName(dns.Fqdn(fqdn))
+
+	for i := 0; i < len(labels); i++ {
+		zone := dns.Fqdn(strings.Join(labels[i:], "."))
+
+		m := s.newQuery(zone, dns.TypeSOA)
+		r, err := s.exchange(m, s.nameserver)
+		if err != nil {
+			continue
+		}
+
+		// A CNAME-at-apex response has no SOA in Answer or Authority;
+		// soaOwnerName returns "" and the walk keeps moving up to the
+		// parent zone.
+		soaOwner := soaOwnerName(r)
+		if soaOwner == "" {
+			continue
+		}
+
+		if addrs, ok := s.cachedZoneNS(soaOwner); ok {
+			return addrs, nil
+		}
+
+		addrs, err := s.lookupNSAddresses(soaOwner)
+		if err != nil {
+			return nil, err
+		}
+		s.cacheZoneNS(soaOwner, addrs)
+		return addrs, nil
+	}
+
+	return nil, fmt.Errorf("no authoritative zone found walking up %q to the root", fqdn)
+}
+
+// cachedZoneNS returns the nameserver addresses previously resolved for
+// zone, if any permutation in this run already walked to it.
+func (s *Scanner) cachedZoneNS(zone string) ([]string, bool) {
+	s.zoneNSCacheMu.Lock()
+	defer s.zoneNSCacheMu.Unlock()
+	addrs, ok := s.zoneNSCache[zone]
+	return addrs, ok
+}
+
+// cacheZoneNS records zone's resolved nameserver addresses for reuse by
+// sibling permutations later in this run.
+func (s *Scanner) cacheZoneNS(zone string, addrs []string) {
+	s.zoneNSCacheMu.Lock()
+	defer s.zoneNSCacheMu.Unlock()
+	s.zoneNSCache[zone] = addrs
+}
+
+// authoritativeQuery builds an A/AAAA/MX/NS/TXT/SOA query for dnsDomain with
+// the recursion-desired bit cleared, since it's issued directly against an
+// authoritative nameserver rather than a recursive resolver.
+func (s *Scanner) authoritativeQuery(dnsDomain string, qtype uint16) *dns.Msg {
+	m := s.newQuery(dnsDomain, qtype)
+	m.RecursionDesired = false
+	return m
+}
+
+// soaOwnerName returns the owner name of the SOA record in r, whether it
+// arrived as a direct Answer (r is authoritative for the queried name) or as
+// an Authority-section referral to an ancestor zone. Returns "" when no SOA
+// is present at all.
+func soaOwnerName(r *dns.Msg) string {
+	for _, rr := range r.Answer {
+		if soa, ok := rr.(*dns.SOA); ok {
+			return soa.Hdr.Name
+		}
+	}
+	for _, rr := range r.Ns {
+		if soa, ok := rr.(*dns.SOA); ok {
+			return soa.Hdr.Name
+		}
+	}
+	return ""
+}
+
+// lookupNSAddresses resolves zone's NS record set and then each
+// nameserver's own A/AAAA addresses, rather than relying on whatever glue
+// the parent happened to return, so it also works for NS records that point
+// outside the zone (out-of-bailiwick, no glue available in the referral).
+func (s *Scanner) lookupNSAddresses(zone string) ([]string, error) {
+	m := s.newQuery(zone, dns.TypeNS)
+	r, err := s.exchange(m, s.nameserver)
+	if err != nil {
+		return nil, err
+	}
+
+	var nsNames []string
+	for _, rr := range r.Answer {
+		if ns, ok := rr.(*dns.NS); ok {
+			nsNames = append(nsNames, ns.Ns)
+		}
+	}
+	if len(nsNames) == 0 {
+		return nil, fmt.Errorf("no NS records found for zone %q", zone)
+	}
+
+	var addrs []string
+	for _, ns := range nsNames {
+		addrs = append(addrs, s.lookupGlueAddresses(ns)...)
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no A/AAAA addresses found for zone %q nameservers", zone)
+	}
+
+	return addrs, nil
+}
+
+// lookupGlueAddresses resolves nsName's A and AAAA records into dialable
+// "ip:53" addresses.
+func (s *Scanner) lookupGlueAddresses(nsName string) []string {
+	var addrs []string
+
+	for _, qtype := range [...]uint16{dns.TypeA, dns.TypeAAAA} {
+		m := s.newQuery(nsName, qtype)
+		r, err := s.exchange(m, s.nameserver)
+		if err != nil {
+			continue
+		}
+		for _, rr := range r.Answer {
+			switch a := rr.(type) {
+			case *dns.A:
+				addrs = append(addrs, net.JoinHostPort(a.A.String(), "53"))
+			case *dns.AAAA:
+				addrs = append(addrs, net.JoinHostPort(a.AAAA.String(), "53"))
+			}
+		}
+	}
+
+	return addrs
+}
+
+// queryAuthoritative discovers dnsDomain's authoritative nameservers and
+// queries one of them directly, bypassing the configured recursive resolver
+// entirely. This avoids false negatives from resolver-side NXDOMAIN
+// caching/rate-limiting when sweeping thousands of permutations, and
+// surfaces wildcard A records a recursive resolver might otherwise mask.
+func (s *Scanner) queryAuthoritative(dnsDomain string, qtype uint16) (*dns.Msg, string, error) {
+	addrs, err := s.findAuthoritativeNS(dnsDomain)
+	if err != nil {
+		return nil, "", err
+	}
+
+	m := s.authoritativeQuery(dnsDomain, qtype)
+	var lastErr error
+	for _, addr := range addrs {
+		r, err := s.exchangeEndpoint(m, endpoint{nameserver: addr})
+		if err == nil {
+			return r, addr, nil
+		}
+		lastErr = err
+	}
+
+	return nil, "", lastErr
+}
@@ -0,0 +1,150 @@
+package scanner
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// startAuthorityDiscoveryServer runs a single mock recursive resolver that
+// answers the SOA/NS/A walk findAuthoritativeNS performs for
+// "www.sub.example.com.": SOA is authoritative at "example.com.", its NS is
+// "ns1.example.com.", and that NS's A record is authAddr.
+func startAuthorityDiscoveryServer(t *testing.T, authAddr string) (string, func()) {
+	server := &dns.Server{
+		Addr: ":0",
+		Net:  "udp",
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			q := r.Question[0]
+			msg := new(dns.Msg)
+			msg.SetReply(r)
+			msg.Authoritative = true
+
+			switch {
+			case q.Qtype == dns.TypeSOA && q.Name == "example.com.":
+				msg.Answer = append(msg.Answer, &dns.SOA{
+					Hdr:  dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 300},
+					Ns:   "ns1.example.com.",
+					Mbox: "hostmaster.example.com.",
+				})
+			case q.Qtype == dns.TypeSOA:
+				// Referral: no SOA in Answer, but Authority carries the
+				// parent zone's SOA, exactly like a real referral.
+				msg.Ns = append(msg.Ns, &dns.SOA{
+					Hdr:  dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 300},
+					Ns:   "ns1.example.com.",
+					Mbox: "hostmaster.example.com.",
+				})
+			case q.Qtype == dns.TypeNS && q.Name == "example.com.":
+				msg.Answer = append(msg.Answer, &dns.NS{
+					Hdr: dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: 300},
+					Ns:  "ns1.example.com.",
+				})
+			case q.Qtype == dns.TypeA && q.Name == "ns1.example.com.":
+				msg.Answer = append(msg.Answer, &dns.A{
+					Hdr: dns.RR_Header{Name: "ns1.example.com.", Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 300},
+					A:   net.ParseIP(authAddr),
+				})
+			}
+
+			w.WriteMsg(msg)
+		}),
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil {
+			t.Logf("mock recursive resolver error: %v", err)
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	return server.PacketConn.LocalAddr().String(), func() { server.Shutdown() }
+}
+
+func TestScanner_FindAuthoritativeNS(t *testing.T) {
+	addr, cleanup := startAuthorityDiscoveryServer(t, "198.51.100.7")
+	defer cleanup()
+
+	s := NewScanner(&Config{Nameservers: addr, Threads: 1})
+
+	addrs, err := s.findAuthoritativeNS("www.sub.example.com")
+	if err != nil {
+		t.Fatalf("findAuthoritativeNS() error = %v", err)
+	}
+	if len(addrs) != 1 || addrs[0] != "198.51.100.7:53" {
+		t.Errorf("findAuthoritativeNS() = %v, want [198.51.100.7:53]", addrs)
+	}
+}
+
+func TestScanner_FindAuthoritativeNS_NoSOAFound(t *testing.T) {
+	server := &dns.Server{
+		Addr:    ":0",
+		Net:     "udp",
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) { w.WriteMsg(r.SetReply(r)) }),
+	}
+	go server.ListenAndServe()
+	time.Sleep(100 * time.Millisecond)
+	defer server.Shutdown()
+	addr := server.PacketConn.LocalAddr().String()
+
+	s := NewScanner(&Config{Nameservers: addr, Threads: 1})
+
+	if _, err := s.findAuthoritativeNS("www.sub.example.com"); err == nil {
+		t.Error("findAuthoritativeNS() error = nil, want an error when no SOA is ever found")
+	}
+}
+
+func TestScanner_FindAuthoritativeNS_CachesZoneNS(t *testing.T) {
+	addr, cleanup := startAuthorityDiscoveryServer(t, "198.51.100.7")
+	defer cleanup()
+
+	s := NewScanner(&Config{Nameservers: addr, Threads: 1})
+
+	if _, err := s.findAuthoritativeNS("www.sub.example.com"); err != nil {
+		t.Fatalf("findAuthoritativeNS() error = %v", err)
+	}
+	if _, ok := s.cachedZoneNS("example.com."); !ok {
+		t.Fatal("expected example.com. to be cached after the first walk")
+	}
+
+	// A sibling permutation sharing the same zone should hit the cache
+	// instead of repeating the SOA/NS/glue walk.
+	addrs, err := s.findAuthoritativeNS("other.example.com")
+	if err != nil {
+		t.Fatalf("findAuthoritativeNS() error = %v", err)
+	}
+	if len(addrs) != 1 || addrs[0] != "198.51.100.7:53" {
+		t.Errorf("findAuthoritativeNS() = %v, want the cached [198.51.100.7:53]", addrs)
+	}
+}
+
+func TestScanner_AuthoritativeQuery_ClearsRecursionDesired(t *testing.T) {
+	s := NewScanner(&Config{Nameservers: "127.0.0.1:1", Threads: 1})
+
+	m := s.authoritativeQuery("example.com", dns.TypeA)
+	if m.RecursionDesired {
+		t.Error("authoritativeQuery() set RecursionDesired, want it cleared for direct authority queries")
+	}
+}
+
+func TestScanner_QueryAuthoritative_PropagatesDiscoveryFailure(t *testing.T) {
+	server := &dns.Server{
+		Addr:    ":0",
+		Net:     "udp",
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) { w.WriteMsg(r.SetReply(r)) }),
+	}
+	go server.ListenAndServe()
+	time.Sleep(100 * time.Millisecond)
+	defer server.Shutdown()
+	addr := server.PacketConn.LocalAddr().String()
+
+	s := NewScanner(&Config{Nameservers: addr, QueryAuthoritative: true, Threads: 1})
+
+	domain := newTestDomain()
+	domain.Domain = "www.sub.example.com"
+	if err := s.lookupA(domain); err == nil {
+		t.Error("lookupA() error = nil, want an error when no authoritative zone can be discovered")
+	}
+}
@@ -0,0 +1,157 @@
+package scanner
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// defaultCacheCapacity bounds the number of cached answers so a long scan
+// against many distinct authorities can't grow the cache unboundedly.
+const defaultCacheCapacity = 4096
+
+// defaultNegativeTTL caps how long an NXDOMAIN/NODATA answer is cached when
+// its SOA carries no usable minimum TTL (RFC 2308).
+const defaultNegativeTTL = 60 * time.Second
+
+// answerCache is a small LRU cache of DNS responses keyed by
+// (qname, qtype, nameserver), used to avoid re-querying the same
+// authoritative answer, or the same NXDOMAIN, for every fuzzed permutation
+// that shares a parent zone.
+type answerCache struct {
+	mu       sync.Mutex
+	ttlCap   time.Duration
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+
+	hits   int64
+	misses int64
+}
+
+type cacheEntry struct {
+	key     string
+	msg     *dns.Msg
+	expires time.Time
+}
+
+// newAnswerCache builds an answerCache capping any cached answer's lifetime
+// at ttlCap (defaultNegativeTTL if <= 0) and holding at most capacity
+// entries (defaultCacheCapacity if <= 0).
+func newAnswerCache(ttlCap time.Duration, capacity int) *answerCache {
+	if ttlCap <= 0 {
+		ttlCap = defaultNegativeTTL
+	}
+	if capacity <= 0 {
+		capacity = defaultCacheCapacity
+	}
+	return &answerCache{
+		ttlCap:   ttlCap,
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// cacheKey builds the (qname, qtype, nameserver) key an answer is stored
+// under.
+func cacheKey(qname string, qtype uint16, nameserver string) string {
+	return fmt.Sprintf("%s|%d|%s", qname, qtype, nameserver)
+}
+
+func (c *answerCache) get(key string) (*dns.Msg, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		atomic.AddInt64(&c.misses, 1)
+		return nil, false
+	}
+
+	entry := el.Value.(*cacheEntry)
+	if time.Now().After(entry.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		atomic.AddInt64(&c.misses, 1)
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+	atomic.AddInt64(&c.hits, 1)
+	return entry.msg.Copy(), true
+}
+
+func (c *answerCache) set(key string, msg *dns.Msg, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	if ttl > c.ttlCap {
+		ttl = c.ttlCap
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*cacheEntry)
+		entry.msg = msg.Copy()
+		entry.expires = time.Now().Add(ttl)
+		c.order.MoveToFront(el)
+		return
+	}
+
+	el := c.order.PushFront(&cacheEntry{key: key, msg: msg.Copy(), expires: time.Now().Add(ttl)})
+	c.entries[key] = el
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (c *answerCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.order = list.New()
+}
+
+func (c *answerCache) metrics() (hits, misses int64) {
+	return atomic.LoadInt64(&c.hits), atomic.LoadInt64(&c.misses)
+}
+
+// answerTTL derives how long msg should be cached: the minimum TTL across
+// its answer records for a positive answer, or the SOA minimum (RFC 2308)
+// for a negative answer (NXDOMAIN or NODATA), falling back to
+// defaultNegativeTTL when no SOA accompanies the negative answer. SERVFAIL
+// and REFUSED are not cacheable under RFC 2308 negative caching and are
+// treated as transport errors elsewhere, so they return 0 (don't cache).
+func answerTTL(msg *dns.Msg) time.Duration {
+	if msg.Rcode == dns.RcodeServerFailure || msg.Rcode == dns.RcodeRefused {
+		return 0
+	}
+
+	if len(msg.Answer) > 0 {
+		min := msg.Answer[0].Header().Ttl
+		for _, rr := range msg.Answer[1:] {
+			if ttl := rr.Header().Ttl; ttl < min {
+				min = ttl
+			}
+		}
+		return time.Duration(min) * time.Second
+	}
+
+	for _, rr := range msg.Ns {
+		if soa, ok := rr.(*dns.SOA); ok {
+			return time.Duration(soa.Minttl) * time.Second
+		}
+	}
+
+	return defaultNegativeTTL
+}
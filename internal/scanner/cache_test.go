@@ -0,0 +1,169 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+func TestAnswerCache_SetGet(t *testing.T) {
+	c := newAnswerCache(time.Minute, 0)
+
+	msg := new(dns.Msg)
+	msg.Answer = append(msg.Answer, &dns.A{Hdr: dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeA, Ttl: 300}})
+
+	key := cacheKey("example.com.", dns.TypeA, "8.8.8.8:53")
+	c.set(key, msg, answerTTL(msg))
+
+	got, ok := c.get(key)
+	if !ok {
+		t.Fatal("get() after set() = false, want true")
+	}
+	if len(got.Answer) != 1 {
+		t.Errorf("len(got.Answer) = %d, want 1", len(got.Answer))
+	}
+
+	hits, misses := c.metrics()
+	if hits != 1 || misses != 0 {
+		t.Errorf("metrics() = (%d, %d), want (1, 0)", hits, misses)
+	}
+}
+
+func TestAnswerCache_Miss(t *testing.T) {
+	c := newAnswerCache(time.Minute, 0)
+
+	if _, ok := c.get(cacheKey("nope.example.", dns.TypeA, "8.8.8.8:53")); ok {
+		t.Fatal("get() on empty cache = true, want false")
+	}
+
+	_, misses := c.metrics()
+	if misses != 1 {
+		t.Errorf("misses = %d, want 1", misses)
+	}
+}
+
+func TestAnswerCache_Expiry(t *testing.T) {
+	c := newAnswerCache(time.Minute, 0)
+
+	msg := new(dns.Msg)
+	msg.Answer = append(msg.Answer, &dns.A{Hdr: dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeA, Ttl: 0}})
+
+	key := cacheKey("example.com.", dns.TypeA, "8.8.8.8:53")
+	c.set(key, msg, time.Millisecond)
+
+	time.Sleep(10 * time.Millisecond)
+
+	if _, ok := c.get(key); ok {
+		t.Error("get() after TTL expiry = true, want false")
+	}
+}
+
+func TestAnswerCache_Clear(t *testing.T) {
+	c := newAnswerCache(time.Minute, 0)
+
+	msg := new(dns.Msg)
+	msg.Answer = append(msg.Answer, &dns.A{Hdr: dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeA, Ttl: 300}})
+	key := cacheKey("example.com.", dns.TypeA, "8.8.8.8:53")
+	c.set(key, msg, answerTTL(msg))
+
+	c.clear()
+
+	if _, ok := c.get(key); ok {
+		t.Error("get() after clear() = true, want false")
+	}
+}
+
+func TestAnswerTTL_Positive(t *testing.T) {
+	msg := new(dns.Msg)
+	msg.Answer = append(msg.Answer,
+		&dns.A{Hdr: dns.RR_Header{Ttl: 300}},
+		&dns.A{Hdr: dns.RR_Header{Ttl: 60}},
+	)
+
+	if ttl := answerTTL(msg); ttl != 60*time.Second {
+		t.Errorf("answerTTL() = %v, want 60s (the minimum RR TTL)", ttl)
+	}
+}
+
+func TestAnswerTTL_NegativeUsesSOAMinimum(t *testing.T) {
+	msg := new(dns.Msg)
+	msg.Rcode = dns.RcodeNameError
+	msg.Ns = append(msg.Ns, &dns.SOA{Hdr: dns.RR_Header{Rrtype: dns.TypeSOA}, Minttl: 120})
+
+	if ttl := answerTTL(msg); ttl != 120*time.Second {
+		t.Errorf("answerTTL() = %v, want 120s (the SOA minimum)", ttl)
+	}
+}
+
+func TestAnswerTTL_NegativeFallsBackToDefault(t *testing.T) {
+	msg := new(dns.Msg)
+	msg.Rcode = dns.RcodeNameError
+
+	if ttl := answerTTL(msg); ttl != defaultNegativeTTL {
+		t.Errorf("answerTTL() = %v, want default %v", ttl, defaultNegativeTTL)
+	}
+}
+
+func TestAnswerTTL_ServerFailureAndRefusedNotCached(t *testing.T) {
+	for _, rcode := range []int{dns.RcodeServerFailure, dns.RcodeRefused} {
+		msg := new(dns.Msg)
+		msg.Rcode = rcode
+		msg.Ns = append(msg.Ns, &dns.SOA{Hdr: dns.RR_Header{Rrtype: dns.TypeSOA}, Minttl: 120})
+
+		if ttl := answerTTL(msg); ttl != 0 {
+			t.Errorf("answerTTL() with Rcode=%d = %v, want 0 (not cacheable)", rcode, ttl)
+		}
+	}
+}
+
+func TestAnswerCache_EvictsOldestOverCapacity(t *testing.T) {
+	c := newAnswerCache(time.Minute, 2)
+
+	msg := new(dns.Msg)
+	msg.Answer = append(msg.Answer, &dns.A{Hdr: dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeA, Ttl: 300}})
+
+	keys := []string{
+		cacheKey("a.example.", dns.TypeA, "8.8.8.8:53"),
+		cacheKey("b.example.", dns.TypeA, "8.8.8.8:53"),
+		cacheKey("c.example.", dns.TypeA, "8.8.8.8:53"),
+	}
+	for _, key := range keys {
+		c.set(key, msg, answerTTL(msg))
+	}
+
+	if _, ok := c.get(keys[0]); ok {
+		t.Error("get() on the oldest entry after exceeding capacity = true, want false (evicted)")
+	}
+	if _, ok := c.get(keys[2]); !ok {
+		t.Error("get() on the newest entry = false, want true")
+	}
+}
+
+func TestScanner_CacheServesRepeatedLookup(t *testing.T) {
+	addr, cleanup := startMockDNSServer(t)
+	defer cleanup()
+
+	s := NewScanner(&Config{Nameservers: addr, Threads: 1})
+
+	domain := newTestDomain()
+	domain.Domain = "example.com"
+	if err := s.lookupA(domain); err != nil {
+		t.Fatalf("lookupA() error = %v", err)
+	}
+	if err := s.lookupA(domain); err != nil {
+		t.Fatalf("second lookupA() error = %v", err)
+	}
+
+	if hits := s.Metrics().CacheHits; hits < 1 {
+		t.Errorf("Metrics().CacheHits = %d, want at least 1 after a repeated lookup", hits)
+	}
+}
+
+func TestScanner_DisableCache(t *testing.T) {
+	s := NewScanner(&Config{Nameservers: "127.0.0.1:1", Threads: 1, DisableCache: true})
+
+	if s.cache != nil {
+		t.Error("s.cache is non-nil, want nil when Config.DisableCache is set")
+	}
+}
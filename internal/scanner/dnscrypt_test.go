@@ -0,0 +1,109 @@
+package scanner
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+// buildStamp constructs a minimal valid sdns:// DNS Stamp for testing, with
+// addr/pk/providerName as the length-prefixed fields.
+func buildStamp(addr string, pk [32]byte, providerName string) string {
+	var data []byte
+	data = append(data, 0x01)               // protocol: DNSCrypt
+	data = append(data, make([]byte, 8)...) // properties bitmask, unused here
+
+	data = append(data, byte(len(addr)))
+	data = append(data, addr...)
+	data = append(data, byte(len(pk)))
+	data = append(data, pk[:]...)
+	data = append(data, byte(len(providerName)))
+	data = append(data, providerName...)
+
+	return "sdns://" + base64.RawURLEncoding.EncodeToString(data)
+}
+
+func TestParseDNSCryptStamp(t *testing.T) {
+	var pk [32]byte
+	for i := range pk {
+		pk[i] = byte(i)
+	}
+	raw := buildStamp("2.dnscrypt.example.net:443", pk, "2.dnscrypt-cert.example.net")
+
+	stamp, err := parseDNSCryptStamp(raw)
+	if err != nil {
+		t.Fatalf("parseDNSCryptStamp() error = %v", err)
+	}
+	if stamp.addr != "2.dnscrypt.example.net:443" {
+		t.Errorf("addr = %v, want port preserved", stamp.addr)
+	}
+	if stamp.providerName != "2.dnscrypt-cert.example.net" {
+		t.Errorf("providerName = %v", stamp.providerName)
+	}
+	if stamp.publicKey != pk {
+		t.Errorf("publicKey = %v, want %v", stamp.publicKey, pk)
+	}
+}
+
+func TestParseDNSCryptStamp_DefaultsPort(t *testing.T) {
+	var pk [32]byte
+	raw := buildStamp("2.dnscrypt.example.net", pk, "2.dnscrypt-cert.example.net")
+
+	stamp, err := parseDNSCryptStamp(raw)
+	if err != nil {
+		t.Fatalf("parseDNSCryptStamp() error = %v", err)
+	}
+	if stamp.addr != "2.dnscrypt.example.net:443" {
+		t.Errorf("addr = %v, want :443 appended", stamp.addr)
+	}
+}
+
+func TestParseDNSCryptStamp_Rejects(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"not a stamp", "https://example.com"},
+		{"invalid base64", "sdns://not valid base64!!"},
+		{"too short", "sdns://" + base64.RawURLEncoding.EncodeToString([]byte{0x01})},
+		{"wrong protocol byte", "sdns://" + base64.RawURLEncoding.EncodeToString(append([]byte{0x02}, make([]byte, 8)...))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseDNSCryptStamp(tt.raw); err == nil {
+				t.Fatalf("parseDNSCryptStamp(%q) expected error, got nil", tt.raw)
+			}
+		})
+	}
+}
+
+func TestNewTransport_DNSCryptMalformedStamp(t *testing.T) {
+	if _, _, err := newTransport("sdns://AQcAAAA"); err == nil {
+		t.Fatal("newTransport() expected error for a truncated DNSCrypt stamp")
+	}
+}
+
+func TestPadDNSCryptQuery(t *testing.T) {
+	padded := padDNSCryptQuery([]byte("hello"))
+	if len(padded)%64 != 0 {
+		t.Errorf("len(padded) = %d, want a multiple of 64", len(padded))
+	}
+	if len(padded) < 256 {
+		t.Errorf("len(padded) = %d, want at least 256", len(padded))
+	}
+	if !strings.HasPrefix(string(padded), "hello") {
+		t.Errorf("padded query does not start with the original payload")
+	}
+	if padded[5] != 0x80 {
+		t.Errorf("padded[5] = 0x%02x, want the 0x80 marker immediately after the payload", padded[5])
+	}
+}
+
+func TestUnpadDNSCryptResponse(t *testing.T) {
+	padded := padDNSCryptQuery([]byte("world"))
+	got := unpadDNSCryptResponse(padded)
+	if string(got) != "world" {
+		t.Errorf("unpadDNSCryptResponse() = %q, want %q", got, "world")
+	}
+}
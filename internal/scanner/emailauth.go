@@ -0,0 +1,198 @@
+package scanner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ducksify/godnstwist/internal/fuzzer"
+
+	"github.com/miekg/dns"
+)
+
+// lookupEmailAuth gathers domain's SPF, DMARC, and (when Config.DKIMSelectors
+// is set) DKIM TXT records, so a permutation that could plausibly send or
+// receive mail can be scored for how easy it would be to spoof.
+func (s *Scanner) lookupEmailAuth(domain *fuzzer.Domain) error {
+	dnsDomain := domain.Domain
+	if domain.Punycode != "" {
+		dnsDomain = domain.Punycode
+	}
+
+	auth := &fuzzer.EmailAuth{}
+
+	if txt, err := s.lookupTXT(dnsDomain); err == nil {
+		if spf := findSPFRecord(txt); spf != "" {
+			auth.SPF = spf
+			auth.SPFMechanisms = parseSPFMechanisms(spf)
+			auth.SPFResolved = s.resolveSPFMechanisms(auth.SPFMechanisms)
+		}
+	}
+
+	if txt, err := s.lookupTXT("_dmarc." + dnsDomain); err == nil {
+		if dmarc := findDMARCRecord(txt); dmarc != "" {
+			parseDMARCRecord(dmarc, auth)
+		}
+	}
+
+	for _, selector := range s.config.DKIMSelectors {
+		selector = strings.TrimSpace(selector)
+		if selector == "" {
+			continue
+		}
+		txt, err := s.lookupTXT(selector + "._domainkey." + dnsDomain)
+		if err != nil || len(txt) == 0 {
+			continue
+		}
+		if auth.DKIMSelectors == nil {
+			auth.DKIMSelectors = make(map[string]string)
+		}
+		auth.DKIMSelectors[selector] = strings.Join(txt, "")
+	}
+
+	domain.EmailAuth = auth
+	return nil
+}
+
+// lookupTXT resolves qname's TXT records, joining each record's
+// length-prefixed character-strings back into a single string the way
+// SPF/DMARC/DKIM publishers expect them read.
+func (s *Scanner) lookupTXT(qname string) ([]string, error) {
+	r, _, err := s.query(qname, dns.TypeTXT)
+	if err != nil {
+		return nil, err
+	}
+	if r.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("TXT lookup for %s failed with code %d", qname, r.Rcode)
+	}
+
+	var records []string
+	for _, ans := range r.Answer {
+		if txt, ok := ans.(*dns.TXT); ok {
+			records = append(records, strings.Join(txt.Txt, ""))
+		}
+	}
+	return records, nil
+}
+
+// findSPFRecord returns the first TXT record that looks like an SPF policy.
+func findSPFRecord(txt []string) string {
+	for _, rec := range txt {
+		if strings.HasPrefix(strings.ToLower(rec), "v=spf1") {
+			return rec
+		}
+	}
+	return ""
+}
+
+// findDMARCRecord returns the first TXT record that looks like a DMARC policy.
+func findDMARCRecord(txt []string) string {
+	for _, rec := range txt {
+		if strings.HasPrefix(strings.ToLower(rec), "v=dmarc1") {
+			return rec
+		}
+	}
+	return ""
+}
+
+// parseSPFMechanisms splits an SPF record's terms (RFC 7208 section 4.6.1)
+// into qualifier/type/value triples, skipping the leading "v=spf1" version
+// term.
+func parseSPFMechanisms(record string) []fuzzer.Mechanism {
+	fields := strings.Fields(record)
+	if len(fields) <= 1 {
+		return nil
+	}
+
+	mechanisms := make([]fuzzer.Mechanism, 0, len(fields)-1)
+	for _, term := range fields[1:] {
+		if term == "" {
+			continue
+		}
+
+		qualifier := ""
+		switch term[0] {
+		case '+', '-', '~', '?':
+			qualifier = term[:1]
+			term = term[1:]
+		}
+
+		typ, value := term, ""
+		if i := strings.IndexAny(term, ":="); i >= 0 {
+			typ, value = term[:i], term[i+1:]
+		}
+
+		mechanisms = append(mechanisms, fuzzer.Mechanism{
+			Qualifier: qualifier,
+			Type:      strings.ToLower(typ),
+			Value:     value,
+		})
+	}
+	return mechanisms
+}
+
+// resolveSPFMechanisms resolves the A records of every "a"/"mx" mechanism
+// that names an explicit host (e.g. "a:mail.example.com"), so the real
+// infrastructure an SPF record authorizes can be compared against a
+// permutation's. Mechanisms with no explicit value (bare "a"/"mx", referring
+// to the domain itself) are left unresolved here; the scanner's own A/MX
+// lookups already cover that case.
+func (s *Scanner) resolveSPFMechanisms(mechanisms []fuzzer.Mechanism) map[string][]string {
+	var resolved map[string][]string
+	for _, m := range mechanisms {
+		if m.Value == "" || (m.Type != "a" && m.Type != "mx") {
+			continue
+		}
+		if resolved != nil {
+			if _, ok := resolved[m.Value]; ok {
+				continue
+			}
+		}
+
+		r, _, err := s.query(m.Value, dns.TypeA)
+		if err != nil || r.Rcode != dns.RcodeSuccess {
+			continue
+		}
+
+		var addrs []string
+		for _, ans := range r.Answer {
+			if a, ok := ans.(*dns.A); ok {
+				addrs = append(addrs, a.A.String())
+			}
+		}
+		if len(addrs) == 0 {
+			continue
+		}
+
+		if resolved == nil {
+			resolved = make(map[string][]string)
+		}
+		resolved[m.Value] = addrs
+	}
+	return resolved
+}
+
+// parseDMARCRecord splits a DMARC record's ";"-separated "tag=value" pairs
+// (RFC 7489 section 6.4) into auth's DMARC fields.
+func parseDMARCRecord(record string, auth *fuzzer.EmailAuth) {
+	for _, part := range strings.Split(record, ";") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key, val := strings.ToLower(strings.TrimSpace(kv[0])), strings.TrimSpace(kv[1])
+
+		switch key {
+		case "p":
+			auth.DMARCPolicy = val
+		case "sp":
+			auth.DMARCSubdomainPolicy = val
+		case "pct":
+			if pct, err := strconv.Atoi(val); err == nil {
+				auth.DMARCPercent = pct
+			}
+		case "rua":
+			auth.DMARCReportURI = val
+		}
+	}
+}
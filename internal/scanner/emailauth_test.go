@@ -0,0 +1,138 @@
+package scanner
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/ducksify/godnstwist/internal/fuzzer"
+	"github.com/miekg/dns"
+)
+
+func TestParseSPFMechanisms(t *testing.T) {
+	record := "v=spf1 include:_spf.google.com a:mail.example.com mx ip4:203.0.113.0/24 ip6:2001:db8::/32 redirect=_spf2.example.com ~all"
+
+	got := parseSPFMechanisms(record)
+	want := []fuzzer.Mechanism{
+		{Type: "include", Value: "_spf.google.com"},
+		{Type: "a", Value: "mail.example.com"},
+		{Type: "mx"},
+		{Type: "ip4", Value: "203.0.113.0/24"},
+		{Type: "ip6", Value: "2001:db8::/32"},
+		{Type: "redirect", Value: "_spf2.example.com"},
+		{Qualifier: "~", Type: "all"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSPFMechanisms() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseDMARCRecord(t *testing.T) {
+	auth := &fuzzer.EmailAuth{}
+	parseDMARCRecord("v=DMARC1; p=reject; sp=quarantine; pct=50; rua=mailto:[email protected]", auth)
+
+	if auth.DMARCPolicy != "reject" {
+		t.Errorf("DMARCPolicy = %q, want %q", auth.DMARCPolicy, "reject")
+	}
+	if auth.DMARCSubdomainPolicy != "quarantine" {
+		t.Errorf("DMARCSubdomainPolicy = %q, want %q", auth.DMARCSubdomainPolicy, "quarantine")
+	}
+	if auth.DMARCPercent != 50 {
+		t.Errorf("DMARCPercent = %d, want 50", auth.DMARCPercent)
+	}
+	if auth.DMARCReportURI != "mailto:[email protected]" {
+		t.Errorf("DMARCReportURI = %q, want %q", auth.DMARCReportURI, "mailto:[email protected]")
+	}
+}
+
+// startMockEmailAuthServer runs a mock authoritative server answering TXT
+// queries for example.com's SPF and DMARC records, a DKIM selector, and the
+// A record an "a:" SPF mechanism resolves, analogous to startMockDNSServer.
+func startMockEmailAuthServer(t testing.TB) (string, func()) {
+	records := map[string]string{
+		"example.com.":                      "v=spf1 a:mail.example.com ~all",
+		"_dmarc.example.com.":               "v=DMARC1; p=reject; pct=100; rua=mailto:[email protected]",
+		"selector1._domainkey.example.com.": "v=DKIM1; k=rsa; p=MIGfMA0GCSq",
+	}
+
+	server := &dns.Server{
+		Addr: ":0",
+		Net:  "udp",
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			msg := new(dns.Msg)
+			msg.SetReply(r)
+			msg.Authoritative = true
+
+			q := r.Question[0]
+			switch {
+			case q.Qtype == dns.TypeTXT:
+				if val, ok := records[q.Name]; ok {
+					msg.Answer = append(msg.Answer, &dns.TXT{
+						Hdr: dns.RR_Header{Name: q.Name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: 300},
+						Txt: []string{val},
+					})
+				}
+			case q.Qtype == dns.TypeA && q.Name == "mail.example.com.":
+				msg.Answer = append(msg.Answer, &dns.A{
+					Hdr: dns.RR_Header{Name: q.Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 300},
+					A:   net.ParseIP("198.51.100.7"),
+				})
+			}
+
+			w.WriteMsg(msg)
+		}),
+	}
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to reserve a UDP port: %v", err)
+	}
+	server.PacketConn = pc
+
+	go server.ActivateAndServe()
+
+	return pc.LocalAddr().String(), func() { server.Shutdown() }
+}
+
+func TestScanner_LookupEmailAuth(t *testing.T) {
+	addr, cleanup := startMockEmailAuthServer(t)
+	defer cleanup()
+
+	s := NewScanner(&Config{
+		Nameservers:   addr,
+		Threads:       1,
+		EmailAuth:     true,
+		DKIMSelectors: []string{"selector1", "missing-selector"},
+	})
+
+	domain := newTestDomain()
+	domain.Domain = "example.com"
+
+	if err := s.lookupEmailAuth(domain); err != nil {
+		t.Fatalf("lookupEmailAuth() error = %v", err)
+	}
+
+	auth := domain.EmailAuth
+	if auth == nil {
+		t.Fatal("domain.EmailAuth is nil")
+	}
+	if auth.SPF == "" {
+		t.Error("EmailAuth.SPF is empty, want the apex SPF record")
+	}
+	if len(auth.SPFMechanisms) != 2 {
+		t.Fatalf("len(SPFMechanisms) = %d, want 2", len(auth.SPFMechanisms))
+	}
+	if addrs := auth.SPFResolved["mail.example.com"]; len(addrs) != 1 || addrs[0] != "198.51.100.7" {
+		t.Errorf("SPFResolved[mail.example.com] = %v, want [198.51.100.7]", addrs)
+	}
+	if auth.DMARCPolicy != "reject" {
+		t.Errorf("DMARCPolicy = %q, want %q", auth.DMARCPolicy, "reject")
+	}
+	if auth.DKIMSelectors["selector1"] == "" {
+		t.Error("DKIMSelectors[selector1] is empty, want the DKIM TXT record")
+	}
+	if _, ok := auth.DKIMSelectors["missing-selector"]; ok {
+		t.Error("DKIMSelectors has an entry for a selector with no published record")
+	}
+}
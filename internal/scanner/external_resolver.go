@@ -0,0 +1,35 @@
+package scanner
+
+import (
+	"context"
+
+	"github.com/miekg/dns"
+)
+
+// ExternalResolver is the DNS-lookup surface Scanner delegates to when
+// Config.Resolver is set, in place of the nameserver strings parsed from
+// Config.Nameservers/Transports. Lookup resolves name for qtype and returns
+// its answer-section records, or an error (including the "no such host"
+// case, the same way the built-in exchange path surfaces NXDOMAIN).
+//
+// This lets callers integrate dnstwist with their own resolver stack, add
+// rate limiting, or record/replay DNS for reproducible scans, none of which
+// is possible when DNS is only ever reached through parseResolvers.
+type ExternalResolver interface {
+	Lookup(ctx context.Context, name string, qtype uint16) ([]dns.RR, error)
+}
+
+// queryExternal adapts Config.Resolver's Lookup result into the (*dns.Msg,
+// resolvedBy, error) shape query's other branches return, so callers
+// downstream (lookupA, lookupMX, ...) don't need to know whether an answer
+// came from the wire or from an injected ExternalResolver.
+func (s *Scanner) queryExternal(dnsDomain string, qtype uint16) (*dns.Msg, string, error) {
+	rrs, err := s.config.Resolver.Lookup(context.Background(), dnsDomain, qtype)
+	if err != nil {
+		return nil, "", err
+	}
+	m := new(dns.Msg)
+	m.Rcode = dns.RcodeSuccess
+	m.Answer = rrs
+	return m, "external", nil
+}
@@ -0,0 +1,94 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/ducksify/godnstwist/internal/fuzzer"
+)
+
+// FuzzNameserversParse exercises parseResolvers (and, transitively,
+// parseNameserver) with arbitrary Config.Nameservers strings, to catch
+// panics on malformed schemes, truncated sdns:// stamps, and garbage
+// weight/ecs/strategy attribute suffixes.
+func FuzzNameserversParse(f *testing.F) {
+	seeds := []string{
+		"",
+		"8.8.8.8",
+		"8.8.8.8:53",
+		"udp://8.8.8.8:53",
+		"tls://1.1.1.1:853",
+		"https://dns.google/dns-query",
+		"sdns://AQcAAAA",
+		"8.8.8.8|weight=2|ecs=203.0.113.0/24|strategy=useIPv4",
+		"8.8.8.8|weight=notanumber|ecs=|strategy=",
+		"ftp://8.8.8.8:53",
+		"1.1.1.1,8.8.8.8|weight=3,sdns://AQcAAAA",
+		",,,",
+		"://",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, raw string) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("parseResolvers(%q) panicked: %v", raw, r)
+			}
+		}()
+
+		parseResolvers(raw)
+	})
+}
+
+// FuzzScannerDomain exercises scanDomain with arbitrary domain name strings
+// against a stub DNS server, to catch panics in the A/MX/NS/DNSSEC lookup
+// chain on malformed or oversized input (invalid UTF-8, malformed Punycode,
+// empty labels).
+func FuzzScannerDomain(f *testing.F) {
+	seeds := []string{
+		"example.com",
+		"xn--e1aybc.xn--p1ai",
+		"xn--zzzzzz.com",
+		"",
+		".",
+		"...",
+		"a.",
+		"\xff\xfe.com",
+		"very-long-label-" + string(make([]byte, 250)) + ".com",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	addr, cleanup := startMockDNSServer(f)
+	f.Cleanup(cleanup)
+
+	s := NewScanner(&Config{
+		Nameservers: addr,
+		Threads:     1,
+		All:         true,
+		MXCheck:     true,
+		NSCheck:     true,
+		DNSSEC:      true,
+	})
+
+	f.Fuzz(func(t *testing.T, name string) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("scanDomain(%q) panicked: %v", name, r)
+			}
+		}()
+
+		domain := &fuzzer.Domain{
+			Fuzzer: "fuzz",
+			Domain: name,
+			DNS:    make(map[string][]string),
+			Banner: make(map[string]string),
+			Whois:  make(map[string]string),
+			LSH:    make(map[string]int),
+		}
+
+		s.scanDomain(domain)
+	})
+}
@@ -0,0 +1,149 @@
+package scanner
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/miekg/dns"
+)
+
+// Metrics reports cumulative counters for the inflight/connection-reuse layer.
+type Metrics struct {
+	InflightHits   int64
+	InflightMisses int64
+	PoolConns      int64
+	CacheHits      int64
+	CacheMisses    int64
+}
+
+// inflightCall represents an exchange in progress or completed for a given key.
+type inflightCall struct {
+	wg  sync.WaitGroup
+	msg *dns.Msg
+	err error
+}
+
+// inflightGroup collapses concurrent identical (qname, qtype, server) queries
+// into a single wire request, analogous to miekg/dns's "Inflight" idea and to
+// golang.org/x/sync/singleflight.
+type inflightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*inflightCall
+
+	hits   int64
+	misses int64
+}
+
+func newInflightGroup() *inflightGroup {
+	return &inflightGroup{calls: make(map[string]*inflightCall)}
+}
+
+// Do executes fn for key, or waits for and reuses an in-flight call already
+// running for the same key. The returned *dns.Msg must not be mutated by
+// callers since it may be shared across multiple waiters.
+func (g *inflightGroup) Do(key string, fn func() (*dns.Msg, error)) (*dns.Msg, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		atomic.AddInt64(&g.hits, 1)
+		g.mu.Unlock()
+		call.wg.Wait()
+		return call.msg, call.err
+	}
+
+	call := new(inflightCall)
+	call.wg.Add(1)
+	g.calls[key] = call
+	atomic.AddInt64(&g.misses, 1)
+	g.mu.Unlock()
+
+	call.msg, call.err = fn()
+	call.wg.Done()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+
+	return call.msg, call.err
+}
+
+func (g *inflightGroup) metrics() (hits, misses int64) {
+	return atomic.LoadInt64(&g.hits), atomic.LoadInt64(&g.misses)
+}
+
+// connPool keeps one persistent TCP/TLS connection per nameserver so large
+// TLD-swap runs don't burn through ephemeral ports re-dialing for every
+// permutation. Access to each pooled connection is serialized by a mutex and
+// queries are pipelined using distinct DNS message IDs.
+type connPool struct {
+	mu    sync.Mutex
+	conns map[string]*pooledConn
+
+	opened int64
+}
+
+type pooledConn struct {
+	mu   sync.Mutex
+	conn *dns.Conn
+}
+
+func newConnPool() *connPool {
+	return &connPool{conns: make(map[string]*pooledConn)}
+}
+
+// exchange sends m to server over a pooled, persistent connection using
+// client, dialing (or redialing after a failure) as needed.
+func (p *connPool) exchange(client *dns.Client, m *dns.Msg, server string) (*dns.Msg, error) {
+	p.mu.Lock()
+	pc, ok := p.conns[server]
+	if !ok {
+		pc = &pooledConn{}
+		p.conns[server] = pc
+	}
+	p.mu.Unlock()
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if pc.conn == nil {
+		conn, err := client.Dial(server)
+		if err != nil {
+			return nil, err
+		}
+		pc.conn = conn
+		atomic.AddInt64(&p.opened, 1)
+	}
+
+	r, _, err := client.ExchangeWithConn(m, pc.conn)
+	if err != nil {
+		// The pooled connection may have gone stale (e.g. idle timeout on the
+		// nameserver side); redial once and retry before giving up.
+		pc.conn.Close()
+		conn, dialErr := client.Dial(server)
+		if dialErr != nil {
+			pc.conn = nil
+			return nil, err
+		}
+		pc.conn = conn
+		atomic.AddInt64(&p.opened, 1)
+		r, _, err = client.ExchangeWithConn(m, pc.conn)
+	}
+
+	return r, err
+}
+
+func (p *connPool) metrics() int64 {
+	return atomic.LoadInt64(&p.opened)
+}
+
+func (p *connPool) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, pc := range p.conns {
+		pc.mu.Lock()
+		if pc.conn != nil {
+			pc.conn.Close()
+		}
+		pc.mu.Unlock()
+	}
+	p.conns = make(map[string]*pooledConn)
+}
@@ -0,0 +1,104 @@
+package scanner
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+func TestInflightGroup_DeduplicatesConcurrentCalls(t *testing.T) {
+	g := newInflightGroup()
+
+	var calls int64
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+
+	// arrived tracks how many goroutines have reached their call to Do; the
+	// shared fn below blocks until all 10 have, so every one of them is
+	// guaranteed to join the single in-flight call instead of racing to
+	// become its own leader once fn returns.
+	var arrived sync.WaitGroup
+	arrived.Add(10)
+
+	results := make([]*dns.Msg, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			arrived.Done()
+			msg, _ := g.Do("example.com|1|8.8.8.8:53|udp", func() (*dns.Msg, error) {
+				atomic.AddInt64(&calls, 1)
+				arrived.Wait()
+				m := new(dns.Msg)
+				m.SetQuestion("example.com.", dns.TypeA)
+				return m, nil
+			})
+			results[i] = msg
+		}(i)
+	}
+
+	close(start)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("expected exactly one underlying call, got %d", calls)
+	}
+	for i, r := range results {
+		if r == nil {
+			t.Fatalf("result %d is nil", i)
+		}
+	}
+
+	_, misses := g.metrics()
+	if misses != 1 {
+		t.Errorf("expected 1 miss, got %d", misses)
+	}
+}
+
+func TestInflightGroup_SequentialCallsAreNotShared(t *testing.T) {
+	g := newInflightGroup()
+
+	var calls int64
+	for i := 0; i < 3; i++ {
+		_, err := g.Do("key", func() (*dns.Msg, error) {
+			atomic.AddInt64(&calls, 1)
+			return new(dns.Msg), nil
+		})
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+	}
+
+	if calls != 3 {
+		t.Errorf("expected 3 sequential calls, got %d", calls)
+	}
+}
+
+func TestScanner_Metrics(t *testing.T) {
+	addr, cleanup := startMockDNSServer(t)
+	defer cleanup()
+
+	config := &Config{
+		Nameservers: addr,
+		UserAgent:   "Mozilla/5.0",
+		Threads:     4,
+	}
+
+	s := NewScanner(config)
+	if s == nil {
+		t.Fatal("Failed to create scanner")
+	}
+
+	m := s.newQuery("example.com", dns.TypeA)
+	if _, err := s.exchange(m, s.nameserver); err != nil {
+		t.Fatalf("exchange() failed: %v", err)
+	}
+
+	metrics := s.Metrics()
+	if metrics.InflightMisses == 0 {
+		t.Error("expected at least one inflight miss after an exchange")
+	}
+}
@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// Resolver describes one configured nameserver endpoint together with
+// optional per-resolver weighting, EDNS Client Subnet, and address-family
+// preference, parsed from a Config.Nameservers entry such as
+// "1.1.1.1:53|weight=2|ecs=1.2.3.0/24|strategy=useIPv6".
+type Resolver struct {
+	Server   string
+	Weight   int
+	ECS      string
+	Strategy string
+
+	transport dnsTransport
+	addr      string
+}
+
+func (r Resolver) String() string {
+	return fmt.Sprintf("%s(weight=%d)", r.Server, r.Weight)
+}
+
+// matchesQType reports whether r should be queried for qtype, per its
+// Strategy attribute: "useIPv4" restricts r to A queries and "useIPv6"
+// restricts it to AAAA queries; any other value (including "" and
+// "useIP") leaves r eligible for every query type.
+func (r Resolver) matchesQType(qtype uint16) bool {
+	switch r.Strategy {
+	case "useIPv4":
+		return qtype == dns.TypeA
+	case "useIPv6":
+		return qtype == dns.TypeAAAA
+	default:
+		return true
+	}
+}
+
+// parseResolvers parses a comma-separated Config.Nameservers string into
+// individual Resolver entries, defaulting Weight to 1. Each entry may carry
+// "|"-separated "key=value" attributes after its address:
+// weight=N, ecs=<CIDR>, strategy=useIP|useIPv4|useIPv6.
+func parseResolvers(raw string) []Resolver {
+	var resolvers []Resolver
+
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Split(entry, "|")
+		r := Resolver{Server: strings.TrimSpace(fields[0]), Weight: 1}
+
+		for _, attr := range fields[1:] {
+			kv := strings.SplitN(attr, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			key, val := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+			switch key {
+			case "weight":
+				if w, err := strconv.Atoi(val); err == nil && w > 0 {
+					r.Weight = w
+				}
+			case "ecs":
+				r.ECS = val
+			case "strategy":
+				r.Strategy = val
+			}
+		}
+
+		resolvers = append(resolvers, r)
+	}
+
+	return resolvers
+}
@@ -0,0 +1,133 @@
+package scanner
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// resolverEWMAAlpha weights each new latency sample against the running
+	// average; higher reacts faster to a resolver getting slower or
+	// recovering.
+	resolverEWMAAlpha = 0.3
+	// resolverQuarantineThreshold is how many consecutive failures
+	// (timeout, REFUSED, SERVFAIL) a resolver must accrue before it is
+	// temporarily skipped in favor of healthier ones.
+	resolverQuarantineThreshold = 3
+	// resolverQuarantineDuration is how long a quarantined resolver is
+	// skipped before being given another chance.
+	resolverQuarantineDuration = 30 * time.Second
+)
+
+// resolverStats tracks one resolver's recent health: an exponentially
+// weighted moving average of exchange latency and a streak of consecutive
+// failures, so query can bias its pick toward resolvers that are actually
+// answering and temporarily quarantine ones that aren't.
+type resolverStats struct {
+	mu                  sync.Mutex
+	ewmaLatency         time.Duration
+	consecutiveFailures int
+	quarantinedUntil    time.Time
+}
+
+// recordSuccess folds a successful exchange's latency into the EWMA and
+// clears any failure streak.
+func (rs *resolverStats) recordSuccess(latency time.Duration) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.consecutiveFailures = 0
+	rs.quarantinedUntil = time.Time{}
+	if rs.ewmaLatency == 0 {
+		rs.ewmaLatency = latency
+		return
+	}
+	rs.ewmaLatency = time.Duration(float64(rs.ewmaLatency)*(1-resolverEWMAAlpha) + float64(latency)*resolverEWMAAlpha)
+}
+
+// recordFailure counts a timeout, REFUSED, or SERVFAIL against the
+// resolver, quarantining it once resolverQuarantineThreshold is reached.
+func (rs *resolverStats) recordFailure() {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.consecutiveFailures++
+	if rs.consecutiveFailures >= resolverQuarantineThreshold {
+		rs.quarantinedUntil = time.Now().Add(resolverQuarantineDuration)
+	}
+}
+
+// quarantined reports whether rs's resolver should be skipped for now.
+func (rs *resolverStats) quarantined() bool {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return time.Now().Before(rs.quarantinedUntil)
+}
+
+// latency returns the resolver's current EWMA latency, zero if it has never
+// succeeded yet (which sorts it ahead of any measured-but-slower resolver,
+// so untested resolvers still get tried).
+func (rs *resolverStats) latency() time.Duration {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.ewmaLatency
+}
+
+// resolverHealthFor returns (creating if necessary) the resolverStats for
+// server, shared across every query() call against it for the life of the
+// Scanner.
+func (s *Scanner) resolverHealthFor(server string) *resolverStats {
+	s.resolverHealthMu.Lock()
+	defer s.resolverHealthMu.Unlock()
+
+	if s.resolverHealth == nil {
+		s.resolverHealth = make(map[string]*resolverStats)
+	}
+	rs, ok := s.resolverHealth[server]
+	if !ok {
+		rs = &resolverStats{}
+		s.resolverHealth[server] = rs
+	}
+	return rs
+}
+
+// selectResolvers narrows candidates down to the resolvers query's "first"
+// strategy should actually fan a request out to: those matching qtype, with
+// quarantined resolvers deprioritized (but not dropped entirely, in case
+// every candidate is currently quarantined), sorted healthiest-first, and
+// capped at limit entries. limit <= 0 means "no cap".
+func selectResolvers(candidates []Resolver, healthOf func(string) *resolverStats, qtype uint16, limit int) []Resolver {
+	var eligible, quarantined []Resolver
+	for _, r := range candidates {
+		if !r.matchesQType(qtype) {
+			continue
+		}
+		if healthOf(r.Server).quarantined() {
+			quarantined = append(quarantined, r)
+			continue
+		}
+		eligible = append(eligible, r)
+	}
+
+	sortResolversByLatency(eligible, healthOf)
+	if len(eligible) == 0 {
+		// Every candidate is quarantined; trying a quarantined resolver
+		// again beats failing the query outright.
+		sortResolversByLatency(quarantined, healthOf)
+		eligible = quarantined
+	}
+
+	if limit > 0 && len(eligible) > limit {
+		eligible = eligible[:limit]
+	}
+	return eligible
+}
+
+// sortResolversByLatency orders resolvers ascending by EWMA latency,
+// untested (zero-latency) resolvers first.
+func sortResolversByLatency(resolvers []Resolver, healthOf func(string) *resolverStats) {
+	sort.SliceStable(resolvers, func(i, j int) bool {
+		return healthOf(resolvers[i].Server).latency() < healthOf(resolvers[j].Server).latency()
+	})
+}
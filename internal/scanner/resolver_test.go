@@ -0,0 +1,286 @@
+package scanner
+
+import (
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ducksify/godnstwist/internal/fuzzer"
+	"github.com/miekg/dns"
+)
+
+func TestParseResolvers(t *testing.T) {
+	resolvers := parseResolvers("1.1.1.1:53|weight=2|ecs=1.2.3.0/24, 8.8.8.8:53|strategy=useIPv4, 9.9.9.9:53")
+
+	if len(resolvers) != 3 {
+		t.Fatalf("len(resolvers) = %d, want 3", len(resolvers))
+	}
+
+	if resolvers[0].Server != "1.1.1.1:53" || resolvers[0].Weight != 2 || resolvers[0].ECS != "1.2.3.0/24" {
+		t.Errorf("resolvers[0] = %+v, want Server=1.1.1.1:53 Weight=2 ECS=1.2.3.0/24", resolvers[0])
+	}
+	if resolvers[1].Server != "8.8.8.8:53" || resolvers[1].Weight != 1 || resolvers[1].Strategy != "useIPv4" {
+		t.Errorf("resolvers[1] = %+v, want Server=8.8.8.8:53 Weight=1 Strategy=useIPv4", resolvers[1])
+	}
+	if resolvers[2].Server != "9.9.9.9:53" || resolvers[2].Weight != 1 {
+		t.Errorf("resolvers[2] = %+v, want Server=9.9.9.9:53 Weight=1 (no attributes)", resolvers[2])
+	}
+}
+
+func TestParseResolvers_Empty(t *testing.T) {
+	if resolvers := parseResolvers(""); resolvers != nil {
+		t.Errorf("parseResolvers(\"\") = %+v, want nil", resolvers)
+	}
+}
+
+func TestResolver_MatchesQType(t *testing.T) {
+	tests := []struct {
+		strategy  string
+		qtype     uint16
+		wantMatch bool
+	}{
+		{"useIPv4", dns.TypeA, true},
+		{"useIPv4", dns.TypeAAAA, false},
+		{"useIPv6", dns.TypeAAAA, true},
+		{"useIPv6", dns.TypeA, false},
+		{"", dns.TypeA, true},
+		{"", dns.TypeAAAA, true},
+		{"useIP", dns.TypeAAAA, true},
+	}
+
+	for _, tt := range tests {
+		r := Resolver{Strategy: tt.strategy}
+		if got := r.matchesQType(tt.qtype); got != tt.wantMatch {
+			t.Errorf("Resolver{Strategy: %q}.matchesQType(%d) = %v, want %v", tt.strategy, tt.qtype, got, tt.wantMatch)
+		}
+	}
+}
+
+func TestScanner_MultiResolver_StrategyExcludesResolver(t *testing.T) {
+	addrV4, cleanupV4 := startAResolver(t, "1.1.1.1")
+	defer cleanupV4()
+	addrV6, cleanupV6 := startAResolver(t, "2.2.2.2")
+	defer cleanupV6()
+
+	s := NewScanner(&Config{
+		Nameservers: fmt.Sprintf("%s|strategy=useIPv4,%s|strategy=useIPv6", addrV4, addrV6),
+		Threads:     1,
+	})
+
+	domain := newTestDomain()
+	if err := s.lookupA(domain); err != nil {
+		t.Fatalf("lookupA() error = %v", err)
+	}
+	if len(domain.DNS["A"]) != 1 || domain.DNS["A"][0] != "1.1.1.1" {
+		t.Errorf("domain.DNS[\"A\"] = %v, want [1.1.1.1] (the useIPv6 resolver should be excluded from A queries)", domain.DNS["A"])
+	}
+}
+
+// startAResolver runs a minimal UDP DNS server that answers every A query for
+// "example.com." with ip, so tests can tell which resolver(s) answered.
+func startAResolver(t *testing.T, ip string) (string, func()) {
+	server := &dns.Server{
+		Addr: ":0",
+		Net:  "udp",
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			msg := new(dns.Msg)
+			msg.SetReply(r)
+			msg.Authoritative = true
+			msg.Answer = append(msg.Answer, &dns.A{
+				Hdr: dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 300},
+				A:   net.ParseIP(ip),
+			})
+			w.WriteMsg(msg)
+		}),
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil {
+			t.Logf("mock resolver %s error: %v", ip, err)
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	return server.PacketConn.LocalAddr().String(), func() { server.Shutdown() }
+}
+
+// startBehaviorResolver runs a minimal UDP DNS server that, for every A
+// query, waits delay then replies with ip (or, if ip is empty, a SERVFAIL),
+// counting how many queries it received in *calls.
+func startBehaviorResolver(t *testing.T, ip string, delay time.Duration, calls *int32) (string, func()) {
+	server := &dns.Server{
+		Addr: ":0",
+		Net:  "udp",
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			atomic.AddInt32(calls, 1)
+			if delay > 0 {
+				time.Sleep(delay)
+			}
+
+			msg := new(dns.Msg)
+			msg.SetReply(r)
+			msg.Authoritative = true
+			if ip == "" {
+				msg.Rcode = dns.RcodeServerFailure
+				w.WriteMsg(msg)
+				return
+			}
+			msg.Answer = append(msg.Answer, &dns.A{
+				Hdr: dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 300},
+				A:   net.ParseIP(ip),
+			})
+			w.WriteMsg(msg)
+		}),
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil {
+			t.Logf("mock resolver error: %v", err)
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	return server.PacketConn.LocalAddr().String(), func() { server.Shutdown() }
+}
+
+func TestScanner_MultiResolver_FirstWinsFastestAndQuarantinesSlow(t *testing.T) {
+	var slowCalls, fastCalls int32
+	slowAddr, cleanupSlow := startBehaviorResolver(t, "3.3.3.3", 200*time.Millisecond, &slowCalls)
+	defer cleanupSlow()
+	fastAddr, cleanupFast := startBehaviorResolver(t, "4.4.4.4", 0, &fastCalls)
+	defer cleanupFast()
+
+	s := NewScanner(&Config{
+		Nameservers: fmt.Sprintf("%s,%s", slowAddr, fastAddr),
+		Threads:     1,
+	})
+
+	domain := newTestDomain()
+	if err := s.lookupA(domain); err != nil {
+		t.Fatalf("lookupA() error = %v", err)
+	}
+	if len(domain.DNS["A"]) != 1 || domain.DNS["A"][0] != "4.4.4.4" {
+		t.Errorf("domain.DNS[\"A\"] = %v, want [4.4.4.4] (the fast resolver should win)", domain.DNS["A"])
+	}
+	if domain.ResolvedBy != fastAddr {
+		t.Errorf("domain.ResolvedBy = %q, want %q", domain.ResolvedBy, fastAddr)
+	}
+}
+
+func TestScanner_MultiResolver_QuarantinesRepeatedlyFailingResolver(t *testing.T) {
+	var servfailCalls, goodCalls int32
+	servfailAddr, cleanupServfail := startBehaviorResolver(t, "", 0, &servfailCalls)
+	defer cleanupServfail()
+	// A small delay on the good resolver guarantees queryFirst's collection
+	// loop always observes the servfail resolver's (immediate) answer first
+	// and keeps waiting for the good one, so the servfail resolver's health
+	// is recorded before lookupA returns - otherwise both being delay=0
+	// races queryFirst's fastest-wins return against the background health
+	// recording for whichever resolver it didn't wait on.
+	goodAddr, cleanupGood := startBehaviorResolver(t, "5.5.5.5", 20*time.Millisecond, &goodCalls)
+	defer cleanupGood()
+
+	s := NewScanner(&Config{
+		Nameservers:       fmt.Sprintf("%s,%s", servfailAddr, goodAddr),
+		ParallelResolvers: 2,
+		Threads:           1,
+		DisableCache:      true,
+	})
+
+	for i := 0; i < resolverQuarantineThreshold; i++ {
+		domain := newTestDomain()
+		if err := s.lookupA(domain); err != nil {
+			t.Fatalf("lookupA() error = %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(&servfailCalls); got != resolverQuarantineThreshold {
+		t.Fatalf("servfailCalls = %d, want %d (one SERVFAIL per lookup before quarantine kicks in)", got, resolverQuarantineThreshold)
+	}
+
+	domain := newTestDomain()
+	if err := s.lookupA(domain); err != nil {
+		t.Fatalf("lookupA() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&servfailCalls); got != resolverQuarantineThreshold {
+		t.Errorf("servfailCalls = %d after quarantine, want unchanged %d (quarantined resolver should be skipped)", got, resolverQuarantineThreshold)
+	}
+	if len(domain.DNS["A"]) != 1 || domain.DNS["A"][0] != "5.5.5.5" {
+		t.Errorf("domain.DNS[\"A\"] = %v, want [5.5.5.5] from the still-healthy resolver", domain.DNS["A"])
+	}
+}
+
+func newTestDomain() *fuzzer.Domain {
+	return &fuzzer.Domain{
+		Domain: "example.com",
+		DNS:    make(map[string][]string),
+		Banner: make(map[string]string),
+		Whois:  make(map[string]string),
+		LSH:    make(map[string]int),
+	}
+}
+
+func TestScanner_MultiResolver_First(t *testing.T) {
+	addrA, cleanupA := startAResolver(t, "1.1.1.1")
+	defer cleanupA()
+	addrB, cleanupB := startAResolver(t, "2.2.2.2")
+	defer cleanupB()
+
+	s := NewScanner(&Config{Nameservers: fmt.Sprintf("%s,%s", addrA, addrB), Threads: 1})
+
+	domain := newTestDomain()
+	if err := s.lookupA(domain); err != nil {
+		t.Fatalf("lookupA() error = %v", err)
+	}
+	if len(domain.DNS["A"]) != 1 {
+		t.Fatalf("len(domain.DNS[\"A\"]) = %d, want 1", len(domain.DNS["A"]))
+	}
+	if domain.ResolvedBy == "" {
+		t.Error("domain.ResolvedBy is empty, want the resolver that answered")
+	}
+}
+
+func TestScanner_MultiResolver_All(t *testing.T) {
+	addrA, cleanupA := startAResolver(t, "1.1.1.1")
+	defer cleanupA()
+	addrB, cleanupB := startAResolver(t, "2.2.2.2")
+	defer cleanupB()
+
+	s := NewScanner(&Config{
+		Nameservers:      fmt.Sprintf("%s,%s", addrA, addrB),
+		ResolverStrategy: "all",
+		Threads:          1,
+	})
+
+	domain := newTestDomain()
+	if err := s.lookupA(domain); err != nil {
+		t.Fatalf("lookupA() error = %v", err)
+	}
+	if len(domain.DNS["A"]) != 2 {
+		t.Fatalf("len(domain.DNS[\"A\"]) = %d, want 2 merged answers", len(domain.DNS["A"]))
+	}
+}
+
+func TestScanner_MultiResolver_Majority(t *testing.T) {
+	addrA, cleanupA := startAResolver(t, "1.1.1.1")
+	defer cleanupA()
+	addrB, cleanupB := startAResolver(t, "1.1.1.1")
+	defer cleanupB()
+	addrC, cleanupC := startAResolver(t, "9.9.9.9")
+	defer cleanupC()
+
+	s := NewScanner(&Config{
+		Nameservers:      fmt.Sprintf("%s,%s,%s|weight=5", addrA, addrB, addrC),
+		ResolverStrategy: "majority",
+		Threads:          1,
+	})
+
+	domain := newTestDomain()
+	if err := s.lookupA(domain); err != nil {
+		t.Fatalf("lookupA() error = %v", err)
+	}
+	if len(domain.DNS["A"]) != 1 || domain.DNS["A"][0] != "9.9.9.9" {
+		t.Errorf("domain.DNS[\"A\"] = %v, want [9.9.9.9] (highest-weight resolver wins)", domain.DNS["A"])
+	}
+}
@@ -1,8 +1,10 @@
 package scanner
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -18,11 +20,41 @@ type Scanner struct {
 	geoipDB    *geoip2.Reader
 	dnsClient  *dns.Client
 	nameserver string
+	transport  dnsTransport
+	endpoints  []endpoint
+	resolvers  []Resolver
+	inflight   *inflightGroup
+	pool       *connPool
+	cache      *answerCache
+
+	// resolverHealth tracks per-resolver EWMA latency and failure streaks
+	// (see resolver_health.go), used by query's "first" strategy to bias
+	// its fan-out toward healthy resolvers and quarantine unhealthy ones.
+	resolverHealthMu sync.Mutex
+	resolverHealth   map[string]*resolverStats
+
+	// zoneNSCache memoizes findAuthoritativeNS's zone->nameserver-address
+	// lookup by zone name, so sibling permutations sharing a parent zone
+	// (the common case when fuzzing one domain) only walk and resolve that
+	// zone's NS set once per run. Kept independent of the TTL-based answer
+	// cache so it still applies when Config.DisableCache is set.
+	zoneNSCacheMu sync.Mutex
+	zoneNSCache   map[string][]string
+}
+
+// endpoint pairs a resolved nameserver address with the transport used to
+// reach it, so Scanner can try several in order when Config.TransportFallback
+// is set.
+type endpoint struct {
+	nameserver string
+	transport  dnsTransport
 }
 
 type Config struct {
 	All         bool
 	Banners     bool
+	DNSSEC      bool
+	EDNS        EDNSConfig
 	GeoIP       bool
 	LSH         string
 	MXCheck     bool
@@ -32,6 +64,105 @@ type Config struct {
 	Screenshots string
 	UserAgent   string
 	Threads     int
+	// ZoneWalk enables the aggressive NSEC/NSEC3 zone-walking fuzzer, which
+	// issues many additional queries against the authority to enumerate or
+	// reverse sibling owner names. Opt-in only.
+	ZoneWalk bool
+	// Transports is an ordered list of nameserver endpoints (URI-style
+	// schemes such as tls://, https://, quic://, or plain host:port), tried
+	// in order when TransportFallback is set. Takes precedence over
+	// Nameservers when non-empty.
+	Transports []string
+	// TransportFallback makes lookupA/lookupMX/lookupNS retry against each
+	// subsequent entry in Transports (or Nameservers) on timeout/error,
+	// instead of only ever using the first one.
+	TransportFallback bool
+	// ForceTCP skips UDP entirely, issuing every plain/unencrypted query
+	// over TCP. Truncated UDP responses are otherwise retried over TCP
+	// automatically.
+	ForceTCP bool
+	// ResolverStrategy controls how A/MX/NS queries are aggregated when
+	// Nameservers configures more than one resolver: "first" (default)
+	// returns the first authoritative answer, "all" merges every resolver's
+	// answers, and "majority" returns the answer set agreed on by the
+	// highest total resolver weight.
+	ResolverStrategy string
+	// ParallelResolvers caps how many resolvers the "first" strategy (the
+	// default) fans a single query out to at once, preferring the
+	// healthiest ones (see resolver_health.go); the rest are only tried if
+	// every preferred resolver is currently quarantined. Defaults to 2 when
+	// zero or negative. Ignored by the "all" and "majority" strategies,
+	// which always query every configured resolver.
+	ParallelResolvers int
+	// DisableCache turns off the scanner's in-process DNS answer cache.
+	// Caching is on by default, since scanning hundreds of permutations
+	// against the same authoritative nameservers otherwise sends massive
+	// duplicate traffic (e.g. the same parent NS set for every permutation
+	// in lookupNS). Set true for one-shot scans that want every query to
+	// hit the wire.
+	DisableCache bool
+	// CacheTTLCap bounds how long any single cached answer (positive or
+	// negative) is kept, regardless of the TTL/SOA minimum the nameserver
+	// reported. Defaults to 60s when zero.
+	CacheTTLCap time.Duration
+	// CacheSize bounds how many answers the cache holds at once, evicting
+	// the least recently used entry once exceeded. Defaults to 4096 when
+	// zero or negative.
+	CacheSize int
+	// QueryStrategy selects which address family is queried and considered
+	// for HasARecords/registration classification: "useIPv4" issues only
+	// A queries, "useIPv6" issues only AAAA queries, and "" or "useIP"
+	// (the default) issues both.
+	QueryStrategy string
+	// QueryAuthoritative makes lookupA/lookupMX/lookupNS discover and query
+	// each domain's authoritative nameserver directly, with the recursion
+	// desired bit cleared, instead of going through the configured
+	// recursive resolver. Reduces false negatives from resolver-side
+	// NXDOMAIN caching/rate-limiting when sweeping many permutations, and
+	// surfaces wildcard A records a recursive resolver may otherwise mask.
+	// The zone->nameserver mapping discovered for one permutation is
+	// reused by every sibling permutation under the same parent zone for
+	// the life of the Scanner.
+	QueryAuthoritative bool
+	// EmailAuth enables SPF/DMARC/DKIM lookup and parsing for triaging a
+	// permutation's email-abuse exposure, once it has resolved MX records.
+	// Parallel to MXCheck, which only checks whether mail can be routed to
+	// the domain at all.
+	EmailAuth bool
+	// DKIMSelectors, when EmailAuth is set, additionally queries
+	// "<selector>._domainkey.<domain>" for each listed selector (DKIM has
+	// no discovery mechanism, so the caller must supply candidates, e.g.
+	// "google", "selector1", "default").
+	DKIMSelectors []string
+	// Resolver, when set, replaces the entire nameserver-string DNS path
+	// (Nameservers, Transports, QueryAuthoritative, ResolverStrategy) with
+	// a caller-provided DNS backend. Takes precedence over all of them.
+	Resolver ExternalResolver
+}
+
+// EDNSConfig describes EDNS0 options to attach to every outgoing query.
+type EDNSConfig struct {
+	// ClientSubnet is an EDNS0 Client Subnet address in CIDR form (e.g.
+	// "203.0.113.0/24") used to emulate a resolver in a given region.
+	ClientSubnet string
+	// NSID requests that the nameserver identify itself (RFC 5001).
+	NSID bool
+	// Cookie enables DNS Cookies (RFC 7873) with a client-generated cookie.
+	Cookie bool
+	// Padding, when > 0, pads the query to a multiple of this many bytes (RFC 7830).
+	Padding int
+	// Options is a pass-through list of additional raw EDNS0 options.
+	Options []EDNSOption
+}
+
+// EDNSOption is a raw (code, data) EDNS0 option passed through verbatim.
+type EDNSOption struct {
+	Code uint16
+	Data []byte
+}
+
+func (o EDNSOption) Option() dns.EDNS0 {
+	return &dns.EDNS0_LOCAL{Code: o.Code, Data: o.Data}
 }
 
 func NewScanner(config *Config) *Scanner {
@@ -46,19 +177,381 @@ func NewScanner(config *Config) *Scanner {
 		}
 	}
 
-	if config.Nameservers != "" {
-		s.nameserver = strings.Split(config.Nameservers, ",")[0]
-	} else {
-		s.nameserver = "8.8.8.8:53"
+	var rawEndpoints []string
+	switch {
+	case len(config.Transports) > 0:
+		rawEndpoints = config.Transports
+	case config.Nameservers != "":
+		rawEndpoints = strings.Split(config.Nameservers, ",")
+	default:
+		// Prefer whatever resolver(s) the host is already configured to use
+		// over a hardcoded default, so behavior matches the rest of the
+		// system's DNS resolution.
+		rawEndpoints = systemNameservers()
+		if len(rawEndpoints) == 0 {
+			rawEndpoints = []string{"8.8.8.8:53"}
+		}
 	}
 
 	s.dnsClient = &dns.Client{
 		Net: "udp",
 	}
 
+	// Build a transport matching each endpoint's URI scheme, if any (tls://,
+	// https://, quic://). A plain "host:port" keeps defaulting to UDP.
+	for _, raw := range rawEndpoints {
+		raw = strings.TrimSpace(raw)
+		transport, addr, err := newTransport(raw)
+		if err != nil {
+			s.endpoints = append(s.endpoints, endpoint{nameserver: raw})
+			continue
+		}
+		if config.ForceTCP {
+			if ct, ok := transport.(*clientTransport); ok && ct.client.Net == "udp" {
+				transport = &clientTransport{client: &dns.Client{Net: "tcp"}, addr: addr}
+			}
+		}
+		s.endpoints = append(s.endpoints, endpoint{nameserver: addr, transport: transport})
+	}
+
+	// s.nameserver/s.transport mirror the first endpoint for callers (and
+	// existing DNSSEC/zone-walk helpers) that only need a single address.
+	s.nameserver = s.endpoints[0].nameserver
+	s.transport = s.endpoints[0].transport
+
+	// s.resolvers parses Config.Nameservers' optional per-entry attribute
+	// syntax (weight=, ecs=, strategy=) for the multi-resolver query() path.
+	// It is left with at most one entry for plain, attribute-free configs,
+	// which query() treats as "use the classic single-resolver path".
+	s.resolvers = parseResolvers(config.Nameservers)
+	for i := range s.resolvers {
+		transport, addr, err := newTransport(s.resolvers[i].Server)
+		if err != nil {
+			s.resolvers[i].addr = s.resolvers[i].Server
+			continue
+		}
+		s.resolvers[i].transport = transport
+		s.resolvers[i].addr = addr
+	}
+
+	s.inflight = newInflightGroup()
+	s.pool = newConnPool()
+	s.zoneNSCache = make(map[string][]string)
+	if !config.DisableCache {
+		s.cache = newAnswerCache(config.CacheTTLCap, config.CacheSize)
+	}
+
 	return s
 }
 
+// ClearCache empties the scanner's in-process DNS answer cache. A no-op
+// when Config.DisableCache is set.
+func (s *Scanner) ClearCache() {
+	if s.cache != nil {
+		s.cache.clear()
+	}
+}
+
+// Metrics returns a snapshot of the scanner's inflight-dedup and
+// connection-reuse counters.
+func (s *Scanner) Metrics() Metrics {
+	hits, misses := s.inflight.metrics()
+	m := Metrics{
+		InflightHits:   hits,
+		InflightMisses: misses,
+		PoolConns:      s.pool.metrics(),
+	}
+	if s.cache != nil {
+		m.CacheHits, m.CacheMisses = s.cache.metrics()
+	}
+	return m
+}
+
+// exchange sends m to server, collapsing concurrent identical (qname, qtype,
+// server) queries into a single wire request via the inflight group, and
+// reusing a pooled persistent connection for TCP/TLS transports.
+func (s *Scanner) exchange(m *dns.Msg, server string) (*dns.Msg, error) {
+	q := m.Question[0]
+	key := fmt.Sprintf("%s|%d|%s|%s", q.Name, q.Qtype, server, s.dnsClient.Net)
+
+	return s.inflight.Do(key, func() (*dns.Msg, error) {
+		endpoints := s.endpoints
+		if len(endpoints) == 0 {
+			endpoints = []endpoint{{nameserver: server, transport: s.transport}}
+		}
+
+		var lastErr error
+		var lastServfail *dns.Msg
+		for _, ep := range endpoints {
+			r, err := s.exchangeEndpoint(m, ep)
+			if err == nil && r != nil && r.Rcode == dns.RcodeServerFailure && s.config.TransportFallback {
+				// Treat SERVFAIL like a transport error for fallback purposes:
+				// keep it as a last resort, but prefer trying the next
+				// endpoint first since it may succeed outright.
+				lastServfail = r
+				continue
+			}
+			if err == nil {
+				return r, nil
+			}
+			lastErr = err
+			if !s.config.TransportFallback {
+				break
+			}
+		}
+		if lastServfail != nil {
+			return lastServfail, nil
+		}
+		return nil, lastErr
+	})
+}
+
+// exchangeEndpoint sends m via a single endpoint's transport, routing
+// tcp/tcp-tls through the pooled-connection path like the original
+// single-endpoint exchange did. If the response comes back truncated (as
+// commonly happens for typosquat domains with large NS/MX answer sets),
+// it retries the same query over TCP against the same nameserver. Answers
+// are served from, and saved to, the scanner's answer cache when enabled.
+func (s *Scanner) exchangeEndpoint(m *dns.Msg, ep endpoint) (*dns.Msg, error) {
+	q := m.Question[0]
+	key := cacheKey(q.Name, q.Qtype, ep.nameserver)
+
+	if s.cache != nil {
+		if r, ok := s.cache.get(key); ok {
+			return r, nil
+		}
+	}
+
+	r, err := s.exchangeEndpointOnce(m, ep)
+	if err != nil {
+		return nil, err
+	}
+
+	if r != nil && r.Truncated {
+		if tcpR, tcpErr := s.pool.exchange(&dns.Client{Net: "tcp"}, m, ep.nameserver); tcpErr == nil {
+			r = tcpR
+		}
+	}
+
+	if s.cache != nil && r != nil {
+		s.cache.set(key, r, answerTTL(r))
+	}
+
+	return r, nil
+}
+
+// exchangeEndpointOnce performs a single wire exchange via ep's transport,
+// without any truncation retry.
+func (s *Scanner) exchangeEndpointOnce(m *dns.Msg, ep endpoint) (*dns.Msg, error) {
+	if ct, ok := ep.transport.(*clientTransport); ok {
+		if ct.client.Net == "tcp" || ct.client.Net == "tcp-tls" {
+			return s.pool.exchange(ct.client, m, ep.nameserver)
+		}
+		return ct.Exchange(m)
+	}
+	if ep.transport != nil {
+		return ep.transport.Exchange(m)
+	}
+	r, _, err := s.dnsClient.Exchange(m, ep.nameserver)
+	return r, err
+}
+
+// resolverAnswer pairs one resolver's exchange outcome with the resolver
+// that produced it, shared by all three of query's strategies ("first",
+// "all", "majority").
+type resolverAnswer struct {
+	resolver Resolver
+	r        *dns.Msg
+	err      error
+}
+
+// query resolves dnsDomain/qtype and reports which resolver(s) the returned
+// answer came from. When Config.Nameservers configures a single resolver (the
+// common case), it is equivalent to newQuery+exchange against s.nameserver.
+// When it configures several, they are combined per Config.ResolverStrategy
+// ("first", "all", or "majority"; "first" is the default).
+func (s *Scanner) query(dnsDomain string, qtype uint16) (*dns.Msg, string, error) {
+	if s.config.Resolver != nil {
+		return s.queryExternal(dnsDomain, qtype)
+	}
+
+	if s.config.QueryAuthoritative {
+		return s.queryAuthoritative(dnsDomain, qtype)
+	}
+
+	if len(s.resolvers) <= 1 {
+		m := s.newQuery(dnsDomain, qtype)
+		r, err := s.exchange(m, s.nameserver)
+		return r, s.nameserver, err
+	}
+
+	strategy := s.config.ResolverStrategy
+	if strategy == "" {
+		strategy = "first"
+	}
+
+	if strategy == "first" {
+		return s.queryFirst(dnsDomain, qtype)
+	}
+
+	answers := make([]resolverAnswer, len(s.resolvers))
+	var wg sync.WaitGroup
+	for i, resolver := range s.resolvers {
+		if !resolver.matchesQType(qtype) {
+			answers[i] = resolverAnswer{resolver: resolver, err: fmt.Errorf("resolver %s excluded by strategy %q for this query type", resolver.Server, resolver.Strategy)}
+			continue
+		}
+		wg.Add(1)
+		go func(i int, resolver Resolver) {
+			defer wg.Done()
+			r, err := s.exchangeResolver(dnsDomain, qtype, resolver)
+			answers[i] = resolverAnswer{resolver: resolver, r: r, err: err}
+		}(i, resolver)
+	}
+	wg.Wait()
+
+	switch strategy {
+	case "all":
+		merged := new(dns.Msg)
+		var servers []string
+		for _, a := range answers {
+			if a.err != nil || a.r == nil {
+				continue
+			}
+			merged.Answer = append(merged.Answer, a.r.Answer...)
+			merged.Rcode = a.r.Rcode
+			servers = append(servers, a.resolver.Server)
+		}
+		if len(servers) == 0 {
+			return nil, "", fmt.Errorf("all resolvers failed for %s", dnsDomain)
+		}
+		return merged, strings.Join(servers, ","), nil
+
+	default: // "majority"
+		type vote struct {
+			r       *dns.Msg
+			servers []string
+			weight  int
+		}
+		votes := make(map[string]*vote)
+		for _, a := range answers {
+			if a.err != nil || a.r == nil {
+				continue
+			}
+			sig := answerSignature(a.r)
+			v, ok := votes[sig]
+			if !ok {
+				v = &vote{r: a.r}
+				votes[sig] = v
+			}
+			v.weight += a.resolver.Weight
+			v.servers = append(v.servers, a.resolver.Server)
+		}
+		var winner *vote
+		for _, v := range votes {
+			if winner == nil || v.weight > winner.weight {
+				winner = v
+			}
+		}
+		if winner == nil {
+			return nil, "", fmt.Errorf("all resolvers failed for %s", dnsDomain)
+		}
+		return winner.r, strings.Join(winner.servers, ","), nil
+	}
+}
+
+// queryFirst implements the "first" resolver strategy: it fans dnsDomain/
+// qtype out to at most Config.ParallelResolvers resolvers at once (the
+// healthiest ones, per resolverStats), and returns as soon as any of them
+// answers without a transport error or SERVFAIL, without waiting for the
+// rest. Each resolver's latency/failure outcome is recorded in the
+// background so later calls keep biasing toward whichever resolvers are
+// actually responding and temporarily skip ones that aren't.
+func (s *Scanner) queryFirst(dnsDomain string, qtype uint16) (*dns.Msg, string, error) {
+	limit := s.config.ParallelResolvers
+	if limit <= 0 {
+		limit = 2
+	}
+	resolvers := selectResolvers(s.resolvers, s.resolverHealthFor, qtype, limit)
+	if len(resolvers) == 0 {
+		return nil, "", fmt.Errorf("no resolver accepts query type %d for %s", qtype, dnsDomain)
+	}
+
+	results := make(chan resolverAnswer, len(resolvers))
+	for _, resolver := range resolvers {
+		go func(resolver Resolver) {
+			start := time.Now()
+			r, err := s.exchangeResolver(dnsDomain, qtype, resolver)
+			health := s.resolverHealthFor(resolver.Server)
+			if err != nil || (r != nil && r.Rcode == dns.RcodeServerFailure) || (r != nil && r.Rcode == dns.RcodeRefused) {
+				health.recordFailure()
+			} else {
+				health.recordSuccess(time.Since(start))
+			}
+			results <- resolverAnswer{resolver: resolver, r: r, err: err}
+		}(resolver)
+	}
+
+	var servfail *resolverAnswer
+	for i := 0; i < len(resolvers); i++ {
+		a := <-results
+		if a.err != nil || a.r == nil {
+			continue
+		}
+		if a.r.Rcode == dns.RcodeServerFailure {
+			if servfail == nil {
+				servfail = &a
+			}
+			continue
+		}
+		return a.r, a.resolver.Server, nil
+	}
+	if servfail != nil {
+		return servfail.r, servfail.resolver.Server, nil
+	}
+	return nil, "", fmt.Errorf("all resolvers failed for %s", dnsDomain)
+}
+
+// newResolverQuery builds a query like newQuery, additionally overriding the
+// EDNS Client Subnet with resolver's ECS when it configures one.
+func (s *Scanner) newResolverQuery(dnsDomain string, qtype uint16, resolver Resolver) *dns.Msg {
+	m := s.newQuery(dnsDomain, qtype)
+	if resolver.ECS == "" {
+		return m
+	}
+	subnet := parseClientSubnet(resolver.ECS)
+	if subnet == nil {
+		return m
+	}
+	opt := m.IsEdns0()
+	if opt == nil {
+		m.SetEdns0(4096, false)
+		opt = m.IsEdns0()
+	}
+	opt.Option = append(opt.Option, subnet)
+	return m
+}
+
+// exchangeResolver sends a single resolver-specific query via resolver's own
+// transport, reusing exchangeEndpoint for pooled connections and truncation
+// retry.
+func (s *Scanner) exchangeResolver(dnsDomain string, qtype uint16, resolver Resolver) (*dns.Msg, error) {
+	m := s.newResolverQuery(dnsDomain, qtype, resolver)
+	return s.exchangeEndpoint(m, endpoint{nameserver: resolver.addr, transport: resolver.transport})
+}
+
+// answerSignature reduces a response to its sorted answer records as a
+// single comparable string, used by the "majority" strategy to detect which
+// resolvers agree with each other.
+func answerSignature(r *dns.Msg) string {
+	parts := make([]string, 0, len(r.Answer))
+	for _, rr := range r.Answer {
+		parts = append(parts, rr.String())
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "\n")
+}
+
 func (s *Scanner) Scan(domains []*fuzzer.Domain) []*fuzzer.Domain {
 	var wg sync.WaitGroup
 	results := make([]*fuzzer.Domain, len(domains))
@@ -80,9 +573,50 @@ func (s *Scanner) Scan(domains []*fuzzer.Domain) []*fuzzer.Domain {
 	return results
 }
 
+// ScanStream behaves like Scan but emits each domain on the returned channel
+// as soon as its scan stages complete, instead of collecting every result
+// into a slice first. This lets callers start consuming (and e.g. writing
+// out NDJSON) before the rest of a large permutation set finishes scanning.
+// The channel is closed once every domain has been scanned or ctx is
+// canceled, whichever comes first.
+func (s *Scanner) ScanStream(ctx context.Context, domains []*fuzzer.Domain) <-chan *fuzzer.Domain {
+	out := make(chan *fuzzer.Domain)
+	semaphore := make(chan struct{}, s.config.Threads)
+
+	go func() {
+		defer close(out)
+		var wg sync.WaitGroup
+
+		for _, domain := range domains {
+			select {
+			case <-ctx.Done():
+				wg.Wait()
+				return
+			case semaphore <- struct{}{}:
+			}
+
+			wg.Add(1)
+			go func(domain *fuzzer.Domain) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+
+				scanned := s.scanDomain(domain)
+				select {
+				case out <- scanned:
+				case <-ctx.Done():
+				}
+			}(domain)
+		}
+
+		wg.Wait()
+	}()
+
+	return out
+}
+
 func (s *Scanner) scanDomain(domain *fuzzer.Domain) *fuzzer.Domain {
-	// DNS A record lookup
-	if err := s.lookupA(domain); err != nil {
+	// DNS address record lookup (A and/or AAAA, per Config.QueryStrategy)
+	if err := s.lookupAddresses(domain); err != nil {
 		return domain
 	}
 
@@ -118,26 +652,234 @@ func (s *Scanner) scanDomain(domain *fuzzer.Domain) *fuzzer.Domain {
 		}
 	}
 
+	// SPF/DMARC/DKIM lookup, once the domain is known to route mail
+	if s.config.EmailAuth && len(domain.DNS["MX"]) > 0 {
+		s.lookupEmailAuth(domain)
+	}
+
 	// NS record lookup
 	if s.config.NSCheck {
 		s.lookupNS(domain)
 	}
 
+	// DNSSEC posture lookup
+	if s.config.DNSSEC {
+		s.lookupDNSSEC(domain)
+	}
+
 	return domain
 }
 
-func (s *Scanner) lookupA(domain *fuzzer.Domain) error {
-	// Use Punycode for DNS resolution if available, otherwise use original domain
+// dnssecQuery builds a DNSSEC-aware query for qtype against dnsDomain: it sets
+// the AD (Authenticated Data) flag and, via EDNS0, the DO (DNSSEC OK) bit so
+// compliant resolvers include RRSIG/DNSKEY/NSEC material in the response.
+func (s *Scanner) dnssecQuery(dnsDomain string, qtype uint16) *dns.Msg {
+	m := s.newQuery(dnsDomain, qtype)
+	m.AuthenticatedData = true
+	if opt := m.IsEdns0(); opt != nil {
+		opt.SetDo()
+	} else {
+		m.SetEdns0(4096, true)
+	}
+	return m
+}
+
+// lookupDNSSEC gathers DNSKEY/DS/RRSIG/NSEC records for the domain and
+// records its DNSSEC posture. A domain that returns signed, verifiable
+// records often indicates a copy-pasted zone template; a target that is
+// signed while its lookalike is not (or vice versa) is a useful phishing-kit
+// fingerprint.
+func (s *Scanner) lookupDNSSEC(domain *fuzzer.Domain) error {
 	dnsDomain := domain.Domain
 	if domain.Punycode != "" {
 		dnsDomain = domain.Punycode
 	}
 
+	info := &fuzzer.DNSSECInfo{}
+
+	m := s.dnssecQuery(dnsDomain, dns.TypeDNSKEY)
+	r, err := s.exchange(m, s.nameserver)
+	if err != nil {
+		return err
+	}
+
+	if edns := parseEDNSResponse(r); edns != nil {
+		domain.EDNS = edns
+	}
+
+	var dnskeys []*dns.DNSKEY
+	for _, ans := range r.Answer {
+		switch rr := ans.(type) {
+		case *dns.DNSKEY:
+			info.Signed = true
+			info.Algorithms = appendUniqueUint8(info.Algorithms, rr.Algorithm)
+			info.KeyTags = appendUniqueUint16(info.KeyTags, rr.KeyTag())
+			dnskeys = append(dnskeys, rr)
+		case *dns.RRSIG:
+			info.Signed = true
+			info.Algorithms = appendUniqueUint8(info.Algorithms, rr.Algorithm)
+		}
+	}
+	info.Validated = r.AuthenticatedData && len(dnskeys) > 0
+
+	domain.DNSSEC = info
+	return nil
+}
+
+func appendUniqueUint8(s []uint8, v uint8) []uint8 {
+	for _, existing := range s {
+		if existing == v {
+			return s
+		}
+	}
+	return append(s, v)
+}
+
+func appendUniqueUint16(s []uint16, v uint16) []uint16 {
+	for _, existing := range s {
+		if existing == v {
+			return s
+		}
+	}
+	return append(s, v)
+}
+
+// newQuery builds a question for dnsDomain/qtype and attaches any EDNS0
+// options configured on the scanner (client subnet, NSID, cookie, padding,
+// pass-through options).
+func (s *Scanner) newQuery(dnsDomain string, qtype uint16) *dns.Msg {
 	m := new(dns.Msg)
-	m.SetQuestion(dns.Fqdn(dnsDomain), dns.TypeA)
+	m.SetQuestion(dns.Fqdn(dnsDomain), qtype)
 	m.RecursionDesired = true
+	s.applyEDNS(m)
+	return m
+}
+
+// applyEDNS advertises a 4096-byte UDP payload size on every outgoing query,
+// so answers with large NS/MX/ALL record sets are less likely to come back
+// truncated in the first place, and attaches any EDNS0 options configured on
+// the scanner (client subnet, NSID, cookie, padding, pass-through options).
+func (s *Scanner) applyEDNS(m *dns.Msg) {
+	m.SetEdns0(4096, false)
+	opt := m.IsEdns0()
+
+	cfg := s.config.EDNS
+	if cfg.ClientSubnet == "" && !cfg.NSID && !cfg.Cookie && cfg.Padding == 0 && len(cfg.Options) == 0 {
+		return
+	}
+
+	if cfg.ClientSubnet != "" {
+		if subnet := parseClientSubnet(cfg.ClientSubnet); subnet != nil {
+			opt.Option = append(opt.Option, subnet)
+		}
+	}
+	if cfg.NSID {
+		opt.Option = append(opt.Option, &dns.EDNS0_NSID{Code: dns.EDNS0NSID})
+	}
+	if cfg.Cookie {
+		opt.Option = append(opt.Option, &dns.EDNS0_COOKIE{Code: dns.EDNS0COOKIE, Cookie: fmt.Sprintf("%016x", time.Now().UnixNano())})
+	}
+	if cfg.Padding > 0 {
+		opt.Option = append(opt.Option, &dns.EDNS0_PADDING{Padding: make([]byte, cfg.Padding)})
+	}
+	for _, o := range cfg.Options {
+		opt.Option = append(opt.Option, o.Option())
+	}
+}
+
+// parseClientSubnet builds an EDNS0 Client Subnet option from a CIDR string.
+func parseClientSubnet(cidr string) *dns.EDNS0_SUBNET {
+	ip, ipnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil
+	}
+
+	ones, _ := ipnet.Mask.Size()
+	subnet := &dns.EDNS0_SUBNET{
+		Code:          dns.EDNS0SUBNET,
+		SourceNetmask: uint8(ones),
+		Address:       ip,
+	}
+	if ip.To4() != nil {
+		subnet.Family = 1
+	} else {
+		subnet.Family = 2
+	}
+	return subnet
+}
 
-	r, _, err := s.dnsClient.Exchange(m, s.nameserver)
+// parseEDNSResponse extracts NSID, padding length, and RFC 8914 extended
+// error information from the OPT pseudo-RR of a response, if present.
+func parseEDNSResponse(r *dns.Msg) *fuzzer.EDNSInfo {
+	opt := r.IsEdns0()
+	if opt == nil {
+		return nil
+	}
+
+	info := &fuzzer.EDNSInfo{ExtendedErrorCode: -1}
+	for _, o := range opt.Option {
+		switch v := o.(type) {
+		case *dns.EDNS0_NSID:
+			info.NSID = v.Nsid
+		case *dns.EDNS0_PADDING:
+			info.Padding = len(v.Padding)
+		case *dns.EDNS0_EDE:
+			info.ExtendedErrorCode = int(v.InfoCode)
+			info.ExtendedErrorText = v.ExtraText
+		}
+	}
+	return info
+}
+
+// queryV4/queryV6 report whether Config.QueryStrategy calls for A/AAAA
+// queries respectively. An empty strategy behaves like "useIP": both.
+func (s *Scanner) queryV4() bool {
+	return s.config.QueryStrategy != "useIPv6"
+}
+
+func (s *Scanner) queryV6() bool {
+	return s.config.QueryStrategy == "useIPv6" || s.config.QueryStrategy == "useIP" || s.config.QueryStrategy == ""
+}
+
+// lookupAddresses resolves domain's A and/or AAAA records, per
+// Config.QueryStrategy, succeeding as long as at least one requested family
+// resolved without error.
+func (s *Scanner) lookupAddresses(domain *fuzzer.Domain) error {
+	var lastErr error
+	resolved := false
+
+	if s.queryV4() {
+		if err := s.lookupA(domain); err != nil {
+			lastErr = err
+		} else {
+			resolved = true
+		}
+	}
+
+	if s.queryV6() {
+		if err := s.lookupAAAA(domain); err != nil {
+			if lastErr == nil {
+				lastErr = err
+			}
+		} else {
+			resolved = true
+		}
+	}
+
+	if !resolved {
+		return lastErr
+	}
+	return nil
+}
+
+func (s *Scanner) lookupA(domain *fuzzer.Domain) error {
+	// Use Punycode for DNS resolution if available, otherwise use original domain
+	dnsDomain := domain.Domain
+	if domain.Punycode != "" {
+		dnsDomain = domain.Punycode
+	}
+
+	r, resolvedBy, err := s.query(dnsDomain, dns.TypeA)
 	if err != nil {
 		return err
 	}
@@ -152,6 +894,41 @@ func (s *Scanner) lookupA(domain *fuzzer.Domain) error {
 		}
 	}
 
+	if edns := parseEDNSResponse(r); edns != nil {
+		domain.EDNS = edns
+	}
+
+	domain.ResolvedBy = resolvedBy
+
+	return nil
+}
+
+// lookupAAAA is lookupA's IPv6 counterpart, populating domain.DNS["AAAA"].
+func (s *Scanner) lookupAAAA(domain *fuzzer.Domain) error {
+	dnsDomain := domain.Domain
+	if domain.Punycode != "" {
+		dnsDomain = domain.Punycode
+	}
+
+	r, resolvedBy, err := s.query(dnsDomain, dns.TypeAAAA)
+	if err != nil {
+		return err
+	}
+
+	if r.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("DNS lookup failed with code %d", r.Rcode)
+	}
+
+	for _, ans := range r.Answer {
+		if aaaa, ok := ans.(*dns.AAAA); ok {
+			domain.DNS["AAAA"] = append(domain.DNS["AAAA"], aaaa.AAAA.String())
+		}
+	}
+
+	if domain.ResolvedBy == "" {
+		domain.ResolvedBy = resolvedBy
+	}
+
 	return nil
 }
 
@@ -162,11 +939,7 @@ func (s *Scanner) lookupMX(domain *fuzzer.Domain) error {
 		dnsDomain = domain.Punycode
 	}
 
-	m := new(dns.Msg)
-	m.SetQuestion(dns.Fqdn(dnsDomain), dns.TypeMX)
-	m.RecursionDesired = true
-
-	r, _, err := s.dnsClient.Exchange(m, s.nameserver)
+	r, _, err := s.query(dnsDomain, dns.TypeMX)
 	if err != nil {
 		return err
 	}
@@ -191,11 +964,7 @@ func (s *Scanner) lookupNS(domain *fuzzer.Domain) error {
 		dnsDomain = domain.Punycode
 	}
 
-	m := new(dns.Msg)
-	m.SetQuestion(dns.Fqdn(dnsDomain), dns.TypeNS)
-	m.RecursionDesired = true
-
-	r, _, err := s.dnsClient.Exchange(m, s.nameserver)
+	r, _, err := s.query(dnsDomain, dns.TypeNS)
 	if err != nil {
 		return err
 	}
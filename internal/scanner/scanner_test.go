@@ -1,6 +1,7 @@
 package scanner
 
 import (
+	"encoding/hex"
 	"net"
 	"testing"
 	"time"
@@ -90,7 +91,13 @@ func TestNewScanner_EmptyNameservers(t *testing.T) {
 		t.Fatal("NewScanner() returned nil")
 	}
 
+	// An empty Nameservers falls through to whatever the host's
+	// resolv.conf (or platform equivalent) discovers, only defaulting to
+	// Google DNS when the host has no usable system resolver.
 	expected := "8.8.8.8:53"
+	if system := systemNameservers(); len(system) > 0 {
+		expected = system[0]
+	}
 	if s.nameserver != expected {
 		t.Errorf("Expected default nameserver %s, got %s", expected, s.nameserver)
 	}
@@ -606,7 +613,7 @@ func TestScanner_scanDomain_WithInvalidDomain(t *testing.T) {
 }
 
 // Mock DNS server for testing
-func startMockDNSServer(t *testing.T) (string, func()) {
+func startMockDNSServer(t testing.TB) (string, func()) {
 	server := &dns.Server{
 		Addr: ":0", // Use random port
 		Net:  "udp",
@@ -623,6 +630,14 @@ func startMockDNSServer(t *testing.T) (string, func()) {
 				})
 			}
 
+			// Add AAAA record for example.com
+			if r.Question[0].Name == "example.com." && r.Question[0].Qtype == dns.TypeAAAA {
+				msg.Answer = append(msg.Answer, &dns.AAAA{
+					Hdr:  dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeAAAA, Class: dns.ClassINET, Ttl: 300},
+					AAAA: net.ParseIP("2606:2800:220:1:248:1893:25c8:1946"),
+				})
+			}
+
 			// Add MX record for example.com
 			if r.Question[0].Name == "example.com." && r.Question[0].Qtype == dns.TypeMX {
 				msg.Answer = append(msg.Answer, &dns.MX{
@@ -631,6 +646,38 @@ func startMockDNSServer(t *testing.T) (string, func()) {
 				})
 			}
 
+			// Echo back NSID on the OPT record so EDNS0 plumbing can be tested
+			if opt := r.IsEdns0(); opt != nil {
+				for _, o := range opt.Option {
+					if _, ok := o.(*dns.EDNS0_NSID); ok {
+						reply := new(dns.OPT)
+						reply.Hdr.Name = "."
+						reply.Hdr.Rrtype = dns.TypeOPT
+						reply.Option = append(reply.Option, &dns.EDNS0_NSID{Code: dns.EDNS0NSID, Nsid: hex.EncodeToString([]byte("mockns1"))})
+						msg.Extra = append(msg.Extra, reply)
+					}
+				}
+			}
+
+			// Add DNSKEY/RRSIG records for example.com, simulating a signed zone
+			if r.Question[0].Name == "example.com." && r.Question[0].Qtype == dns.TypeDNSKEY {
+				msg.AuthenticatedData = true
+				msg.Answer = append(msg.Answer, &dns.DNSKEY{
+					Hdr:       dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 300},
+					Flags:     257,
+					Protocol:  3,
+					Algorithm: dns.RSASHA256,
+					PublicKey: "AwEAAaz",
+				})
+				msg.Answer = append(msg.Answer, &dns.RRSIG{
+					Hdr:         dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: 300},
+					TypeCovered: dns.TypeDNSKEY,
+					Algorithm:   dns.RSASHA256,
+					KeyTag:      12345,
+					SignerName:  "example.com.",
+				})
+			}
+
 			w.WriteMsg(&msg)
 		}),
 	}
@@ -702,3 +749,159 @@ func TestScanner_WithMockDNSServer(t *testing.T) {
 		t.Error("lookupMX() returned no MX records")
 	}
 }
+
+func TestScanner_lookupAAAA(t *testing.T) {
+	addr, cleanup := startMockDNSServer(t)
+	defer cleanup()
+
+	s := NewScanner(&Config{Nameservers: addr, Threads: 4})
+
+	domain := &fuzzer.Domain{
+		Fuzzer: "original",
+		Domain: "example.com",
+		DNS:    make(map[string][]string),
+		Banner: make(map[string]string),
+		Whois:  make(map[string]string),
+		LSH:    make(map[string]int),
+	}
+
+	if err := s.lookupAAAA(domain); err != nil {
+		t.Fatalf("lookupAAAA() failed: %v", err)
+	}
+	if len(domain.DNS["AAAA"]) == 0 {
+		t.Error("lookupAAAA() returned no IPv6 addresses")
+	}
+}
+
+func TestScanner_lookupAddresses_QueryStrategy(t *testing.T) {
+	addr, cleanup := startMockDNSServer(t)
+	defer cleanup()
+
+	tests := []struct {
+		name     string
+		strategy string
+		wantA    bool
+		wantAAAA bool
+	}{
+		{"default queries both", "", true, true},
+		{"useIP queries both", "useIP", true, true},
+		{"useIPv4 queries only A", "useIPv4", true, false},
+		{"useIPv6 queries only AAAA", "useIPv6", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScanner(&Config{Nameservers: addr, Threads: 4, QueryStrategy: tt.strategy})
+
+			domain := &fuzzer.Domain{
+				Fuzzer: "original",
+				Domain: "example.com",
+				DNS:    make(map[string][]string),
+				Banner: make(map[string]string),
+				Whois:  make(map[string]string),
+				LSH:    make(map[string]int),
+			}
+
+			if err := s.lookupAddresses(domain); err != nil {
+				t.Fatalf("lookupAddresses() failed: %v", err)
+			}
+			if gotA := len(domain.DNS["A"]) > 0; gotA != tt.wantA {
+				t.Errorf("A records present = %v, want %v", gotA, tt.wantA)
+			}
+			if gotAAAA := len(domain.DNS["AAAA"]) > 0; gotAAAA != tt.wantAAAA {
+				t.Errorf("AAAA records present = %v, want %v", gotAAAA, tt.wantAAAA)
+			}
+		})
+	}
+}
+
+func TestScanner_lookupDNSSEC(t *testing.T) {
+	addr, cleanup := startMockDNSServer(t)
+	defer cleanup()
+
+	config := &Config{
+		DNSSEC:      true,
+		Nameservers: addr,
+		UserAgent:   "Mozilla/5.0",
+		Threads:     4,
+	}
+
+	s := NewScanner(config)
+	if s == nil {
+		t.Fatal("Failed to create scanner")
+	}
+
+	domain := &fuzzer.Domain{
+		Fuzzer: "original",
+		Domain: "example.com",
+		DNS:    make(map[string][]string),
+		Banner: make(map[string]string),
+		Whois:  make(map[string]string),
+		LSH:    make(map[string]int),
+	}
+
+	if err := s.lookupDNSSEC(domain); err != nil {
+		t.Fatalf("lookupDNSSEC() failed: %v", err)
+	}
+
+	if domain.DNSSEC == nil || !domain.DNSSEC.Signed {
+		t.Error("lookupDNSSEC() expected domain to be reported as signed")
+	}
+	if !domain.DNSSEC.Validated {
+		t.Error("lookupDNSSEC() expected domain to be reported as validated")
+	}
+	if len(domain.DNSSEC.Algorithms) == 0 {
+		t.Error("lookupDNSSEC() expected at least one algorithm to be recorded")
+	}
+}
+
+func TestScanner_lookupA_WithNSID(t *testing.T) {
+	addr, cleanup := startMockDNSServer(t)
+	defer cleanup()
+
+	config := &Config{
+		EDNS:        EDNSConfig{NSID: true},
+		Nameservers: addr,
+		UserAgent:   "Mozilla/5.0",
+		Threads:     4,
+	}
+
+	s := NewScanner(config)
+	if s == nil {
+		t.Fatal("Failed to create scanner")
+	}
+
+	domain := &fuzzer.Domain{
+		Fuzzer: "original",
+		Domain: "example.com",
+		DNS:    make(map[string][]string),
+		Banner: make(map[string]string),
+		Whois:  make(map[string]string),
+		LSH:    make(map[string]int),
+	}
+
+	if err := s.lookupA(domain); err != nil {
+		t.Fatalf("lookupA() failed: %v", err)
+	}
+
+	if domain.EDNS == nil || domain.EDNS.NSID == "" {
+		t.Error("lookupA() expected NSID to be populated from the response OPT record")
+	}
+}
+
+func TestParseClientSubnet(t *testing.T) {
+	subnet := parseClientSubnet("203.0.113.0/24")
+	if subnet == nil {
+		t.Fatal("parseClientSubnet() returned nil for a valid CIDR")
+	}
+	if subnet.SourceNetmask != 24 {
+		t.Errorf("SourceNetmask = %d, want 24", subnet.SourceNetmask)
+	}
+	if subnet.Family != 1 {
+		t.Errorf("Family = %d, want 1 (IPv4)", subnet.Family)
+	}
+
+	if parseClientSubnet("not-a-cidr") != nil {
+		t.Error("parseClientSubnet() should return nil for invalid input")
+	}
+}
@@ -0,0 +1,34 @@
+package scanner
+
+import (
+	"net"
+
+	"github.com/ducksify/godnstwist/internal/resolvconf"
+)
+
+// systemNameservers discovers the recursive resolvers the local machine is
+// already configured to use (via /etc/resolv.conf, or GetAdaptersAddresses on
+// Windows), so NewScanner only reaches for the hardcoded Google DNS fallback
+// when no system resolver can be found. Returns nil if discovery fails or
+// finds nothing to use.
+func systemNameservers() []string {
+	servers, err := resolvconf.DiscoverNameservers()
+	if err != nil || len(servers) == 0 {
+		return nil
+	}
+
+	out := make([]string, 0, len(servers))
+	for _, s := range servers {
+		out = append(out, withDefaultPort(s, "53"))
+	}
+	return out
+}
+
+// withDefaultPort appends ":port" to addr, unless addr already carries a
+// port, handling bracketed IPv6 literals the way net.JoinHostPort does.
+func withDefaultPort(addr, port string) string {
+	if _, _, err := net.SplitHostPort(addr); err == nil {
+		return addr
+	}
+	return net.JoinHostPort(addr, port)
+}
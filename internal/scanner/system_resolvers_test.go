@@ -0,0 +1,22 @@
+package scanner
+
+import "testing"
+
+func TestWithDefaultPort(t *testing.T) {
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"8.8.8.8", "8.8.8.8:53"},
+		{"8.8.8.8:53", "8.8.8.8:53"},
+		{"1.1.1.1:5353", "1.1.1.1:5353"},
+		{"::1", "[::1]:53"},
+		{"[::1]:53", "[::1]:53"},
+	}
+
+	for _, tt := range tests {
+		if got := withDefaultPort(tt.addr, "53"); got != tt.want {
+			t.Errorf("withDefaultPort(%q) = %q, want %q", tt.addr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/quic-go/quic-go"
+)
+
+// Transport identifies the wire protocol used to reach a nameserver.
+type Transport string
+
+const (
+	TransportUDP      Transport = "udp"
+	TransportTCP      Transport = "tcp"
+	TransportDoT      Transport = "dot"
+	TransportDoH      Transport = "doh"
+	TransportDoQ      Transport = "doq"
+	TransportDNSCrypt Transport = "dnscrypt"
+)
+
+// ValidateNameserver reports whether raw is a nameserver entry parseNameserver
+// can build a transport from, so callers (like dnstwist.New) can reject a
+// malformed or unsupported --nameservers/--transports entry up front instead
+// of only discovering it the first time a query is issued.
+func ValidateNameserver(raw string) error {
+	_, _, err := parseNameserver(raw)
+	return err
+}
+
+// parseNameserver splits a nameserver entry into its transport and address,
+// recognizing URI-style schemes (tls://, https://, quic://, sdns://) in
+// addition to the classic plain "host:port" form, which defaults to UDP.
+func parseNameserver(raw string) (Transport, string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", "", fmt.Errorf("empty nameserver")
+	}
+
+	if !strings.Contains(raw, "://") {
+		return TransportUDP, raw, nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid nameserver %q: %w", raw, err)
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "udp":
+		return TransportUDP, u.Host, nil
+	case "tcp":
+		return TransportTCP, u.Host, nil
+	case "tls":
+		host := u.Host
+		if !strings.Contains(host, ":") {
+			host += ":853"
+		}
+		return TransportDoT, host, nil
+	case "quic":
+		host := u.Host
+		if !strings.Contains(host, ":") {
+			host += ":853"
+		}
+		return TransportDoQ, host, nil
+	case "https":
+		return TransportDoH, raw, nil
+	case "sdns":
+		return TransportDNSCrypt, raw, nil
+	default:
+		return "", "", fmt.Errorf("unsupported nameserver scheme %q", u.Scheme)
+	}
+}
+
+// dnsTransport abstracts the wire protocol used to exchange a single DNS
+// message with a nameserver.
+type dnsTransport interface {
+	Exchange(m *dns.Msg) (*dns.Msg, error)
+}
+
+// newTransport builds the dnsTransport appropriate for server, dialing/
+// configuring it based on the scheme recognized by parseNameserver.
+func newTransport(server string) (dnsTransport, string, error) {
+	transport, addr, err := parseNameserver(server)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch transport {
+	case TransportUDP:
+		return &clientTransport{client: &dns.Client{Net: "udp"}, addr: addr}, addr, nil
+	case TransportTCP:
+		return &clientTransport{client: &dns.Client{Net: "tcp"}, addr: addr}, addr, nil
+	case TransportDoT:
+		return &clientTransport{client: &dns.Client{Net: "tcp-tls"}, addr: addr}, addr, nil
+	case TransportDoH:
+		return &dohTransport{url: addr, client: &http.Client{Timeout: 10 * time.Second}}, addr, nil
+	case TransportDoQ:
+		return &doqTransport{addr: addr}, addr, nil
+	case TransportDNSCrypt:
+		stamp, err := parseDNSCryptStamp(addr)
+		if err != nil {
+			return nil, "", err
+		}
+		return newDNSCryptTransport(stamp), stamp.addr, nil
+	default:
+		return nil, "", fmt.Errorf("unsupported transport %q", transport)
+	}
+}
+
+// clientTransport wraps a miekg/dns Client for UDP, TCP, and DoT (tcp-tls).
+type clientTransport struct {
+	client *dns.Client
+	addr   string
+}
+
+func (t *clientTransport) Exchange(m *dns.Msg) (*dns.Msg, error) {
+	r, _, err := t.client.Exchange(m, t.addr)
+	return r, err
+}
+
+// dohTransport implements DNS-over-HTTPS (RFC 8484) by POSTing the wire
+// format of m as application/dns-message.
+type dohTransport struct {
+	url    string
+	client *http.Client
+}
+
+func (t *dohTransport) Exchange(m *dns.Msg) (*dns.Msg, error) {
+	wire, err := m.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, t.url, bytes.NewReader(wire))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DoH query failed with status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return nil, err
+	}
+
+	r := new(dns.Msg)
+	if err := r.Unpack(body); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// doqTransport implements DNS-over-QUIC (RFC 9250) using the "doq" ALPN.
+type doqTransport struct {
+	addr string
+}
+
+func (t *doqTransport) Exchange(m *dns.Msg) (*dns.Msg, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tlsConf := &tls.Config{NextProtos: []string{"doq"}}
+	conn, err := quic.DialAddr(ctx, t.addr, tlsConf, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.CloseWithError(0, "")
+
+	stream, err := conn.OpenStreamSync(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	// RFC 9250 section 4.2.1 requires the DNS Message ID to be 0 on the wire;
+	// compliant servers reset it on the response rather than echoing ours.
+	m.Id = 0
+
+	// RFC 9250 requires QUIC-transported messages to be prefixed with their
+	// length as a two-byte integer, as in classic DNS-over-TCP.
+	wire, err := m.Pack()
+	if err != nil {
+		return nil, err
+	}
+	framed := make([]byte, 2+len(wire))
+	framed[0] = byte(len(wire) >> 8)
+	framed[1] = byte(len(wire))
+	copy(framed[2:], wire)
+
+	if _, err := stream.Write(framed); err != nil {
+		return nil, err
+	}
+	stream.Close()
+
+	resp, err := io.ReadAll(stream)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp) < 2 {
+		return nil, fmt.Errorf("DoQ response too short")
+	}
+
+	r := new(dns.Msg)
+	if err := r.Unpack(resp[2:]); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
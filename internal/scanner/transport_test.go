@@ -0,0 +1,295 @@
+package scanner
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+func TestParseNameserver(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		transport Transport
+		addr      string
+		wantErr   bool
+	}{
+		{"plain host:port", "8.8.8.8:53", TransportUDP, "8.8.8.8:53", false},
+		{"explicit udp", "udp://8.8.8.8:53", TransportUDP, "8.8.8.8:53", false},
+		{"explicit tcp", "tcp://8.8.8.8:53", TransportTCP, "8.8.8.8:53", false},
+		{"dot with port", "tls://1.1.1.1:853", TransportDoT, "1.1.1.1:853", false},
+		{"dot without port defaults to 853", "tls://1.1.1.1", TransportDoT, "1.1.1.1:853", false},
+		{"doh", "https://dns.google/dns-query", TransportDoH, "https://dns.google/dns-query", false},
+		{"doq with port", "quic://dns.adguard.com:853", TransportDoQ, "dns.adguard.com:853", false},
+		{"sdns scheme recognized, stamp parsed lazily by newTransport", "sdns://AQcAAAA", TransportDNSCrypt, "sdns://AQcAAAA", false},
+		{"unsupported scheme", "ftp://8.8.8.8:53", "", "", true},
+		{"empty", "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, addr, err := parseNameserver(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseNameserver(%q) expected error, got nil", tt.raw)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseNameserver(%q) unexpected error: %v", tt.raw, err)
+			}
+			if transport != tt.transport {
+				t.Errorf("transport = %v, want %v", transport, tt.transport)
+			}
+			if addr != tt.addr {
+				t.Errorf("addr = %v, want %v", addr, tt.addr)
+			}
+		})
+	}
+}
+
+func TestNewTransport_DoH(t *testing.T) {
+	transport, addr, err := newTransport("https://cloudflare-dns.com/dns-query")
+	if err != nil {
+		t.Fatalf("newTransport() error = %v", err)
+	}
+	if addr != "https://cloudflare-dns.com/dns-query" {
+		t.Errorf("addr = %v, want the DoH URL unchanged", addr)
+	}
+	if _, ok := transport.(*dohTransport); !ok {
+		t.Errorf("expected a *dohTransport, got %T", transport)
+	}
+}
+
+func TestNewScanner_SelectsTransportFromScheme(t *testing.T) {
+	s := NewScanner(&Config{Nameservers: "tls://1.1.1.1:853", Threads: 1})
+	if s.transport == nil {
+		t.Fatal("expected a non-nil transport for a tls:// nameserver")
+	}
+	if _, ok := s.transport.(*clientTransport); !ok {
+		t.Errorf("expected a *clientTransport for DoT, got %T", s.transport)
+	}
+	if s.nameserver != "1.1.1.1:853" {
+		t.Errorf("nameserver = %v, want scheme stripped", s.nameserver)
+	}
+}
+
+func TestScanner_TransportFallback(t *testing.T) {
+	addr, cleanup := startMockDNSServer(t)
+	defer cleanup()
+
+	s := NewScanner(&Config{
+		Transports:        []string{"127.0.0.1:1", addr},
+		TransportFallback: true,
+		Threads:           1,
+	})
+
+	m := s.newQuery("example.com", dns.TypeA)
+	r, err := s.exchange(m, s.nameserver)
+	if err != nil {
+		t.Fatalf("exchange() error = %v, want it to fall back to the working nameserver", err)
+	}
+	if len(r.Answer) == 0 {
+		t.Fatal("expected an answer from the fallback nameserver")
+	}
+}
+
+func TestScanner_NoTransportFallback(t *testing.T) {
+	addr, cleanup := startMockDNSServer(t)
+	defer cleanup()
+
+	s := NewScanner(&Config{
+		Transports:        []string{"127.0.0.1:1", addr},
+		TransportFallback: false,
+		Threads:           1,
+	})
+
+	m := s.newQuery("example.com", dns.TypeA)
+	if _, err := s.exchange(m, s.nameserver); err == nil {
+		t.Fatal("expected an error when fallback is disabled and the first nameserver is unreachable")
+	}
+}
+
+// startMockDoHHandler serves DNS-over-HTTPS (RFC 8484) answers for
+// example.com/A over an httptest.TLSServer (dohTransport only recognizes
+// https:// nameservers), so it can be exercised end to end without a real
+// upstream. The returned client trusts the server's self-signed certificate.
+func startMockDoHHandler(t testing.TB) (url string, client *http.Client, cleanup func()) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		q := new(dns.Msg)
+		if err := q.Unpack(body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		reply := new(dns.Msg)
+		reply.SetReply(q)
+		if len(q.Question) > 0 && q.Question[0].Name == "example.com." && q.Question[0].Qtype == dns.TypeA {
+			reply.Answer = append(reply.Answer, &dns.A{
+				Hdr: dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 300},
+				A:   net.ParseIP("93.184.216.34"),
+			})
+		}
+
+		wire, err := reply.Pack()
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/dns-message")
+		w.Write(wire)
+	}))
+
+	return ts.URL + "/dns-query", ts.Client(), ts.Close
+}
+
+func TestDoHTransport_MockHandler(t *testing.T) {
+	url, client, cleanup := startMockDoHHandler(t)
+	defer cleanup()
+
+	transport, addr, err := newTransport(url)
+	if err != nil {
+		t.Fatalf("newTransport() error = %v", err)
+	}
+	if addr != url {
+		t.Errorf("addr = %v, want %v", addr, url)
+	}
+	transport.(*dohTransport).client = client
+
+	m := new(dns.Msg)
+	m.SetQuestion("example.com.", dns.TypeA)
+
+	r, err := transport.Exchange(m)
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	if len(r.Answer) != 1 {
+		t.Fatalf("len(Answer) = %d, want 1", len(r.Answer))
+	}
+	a, ok := r.Answer[0].(*dns.A)
+	if !ok || a.A.String() != "93.184.216.34" {
+		t.Errorf("Answer[0] = %v, want A 93.184.216.34", r.Answer[0])
+	}
+}
+
+// generateSelfSignedCert builds an in-memory self-signed certificate for
+// "127.0.0.1", so tests can stand up a TLS listener without touching disk.
+func generateSelfSignedCert(t testing.TB) tls.Certificate {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate() error = %v", err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// startMockDoTServer starts a DNS-over-TLS (RFC 7858) listener answering
+// example.com/A, analogous to startMockDNSServer's plain-UDP mock.
+func startMockDoTServer(t testing.TB) (addr string, cleanup func()) {
+	cert := generateSelfSignedCert(t)
+
+	server := &dns.Server{
+		Addr:      "127.0.0.1:0",
+		Net:       "tcp-tls",
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			msg := dns.Msg{}
+			msg.SetReply(r)
+			msg.Authoritative = true
+
+			if len(r.Question) > 0 && r.Question[0].Name == "example.com." && r.Question[0].Qtype == dns.TypeA {
+				msg.Answer = append(msg.Answer, &dns.A{
+					Hdr: dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 300},
+					A:   net.ParseIP("93.184.216.34"),
+				})
+			}
+
+			w.WriteMsg(&msg)
+		}),
+	}
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", server.TLSConfig)
+	if err != nil {
+		t.Fatalf("tls.Listen() error = %v", err)
+	}
+	server.Listener = listener
+
+	started := make(chan error, 1)
+	server.NotifyStartedFunc = func() { started <- nil }
+	go func() {
+		if err := server.ActivateAndServe(); err != nil {
+			select {
+			case started <- err:
+			default:
+			}
+		}
+	}()
+
+	select {
+	case err := <-started:
+		if err != nil {
+			t.Fatalf("ActivateAndServe() error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for mock DoT server to start")
+	}
+
+	return listener.Addr().String(), func() { server.Shutdown() }
+}
+
+func TestDoTTransport_MockListener(t *testing.T) {
+	addr, cleanup := startMockDoTServer(t)
+	defer cleanup()
+
+	client := &dns.Client{Net: "tcp-tls", TLSConfig: &tls.Config{InsecureSkipVerify: true}}
+	transport := &clientTransport{client: client, addr: addr}
+
+	m := new(dns.Msg)
+	m.SetQuestion("example.com.", dns.TypeA)
+
+	r, err := transport.Exchange(m)
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	if len(r.Answer) != 1 {
+		t.Fatalf("len(Answer) = %d, want 1", len(r.Answer))
+	}
+	a, ok := r.Answer[0].(*dns.A)
+	if !ok || a.A.String() != "93.184.216.34" {
+		t.Errorf("Answer[0] = %v, want A 93.184.216.34", r.Answer[0])
+	}
+}
@@ -0,0 +1,118 @@
+package scanner
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// startTruncatingDNSServers runs a UDP server that always reports the
+// response as truncated (no answer), and a TCP server on the same port that
+// returns the full NS answer set, so tests can assert the UDP-truncated
+// path is recovered over TCP.
+func startTruncatingDNSServers(t *testing.T) (string, func()) {
+	udpConn, err := net.ListenUDP("udp", &net.UDPAddr{})
+	if err != nil {
+		t.Fatalf("failed to reserve a UDP port: %v", err)
+	}
+	addr := udpConn.LocalAddr().(*net.UDPAddr)
+	udpConn.Close()
+
+	fullAnswer := func(r *dns.Msg) *dns.Msg {
+		msg := new(dns.Msg)
+		msg.SetReply(r)
+		msg.Authoritative = true
+		msg.Answer = append(msg.Answer,
+			&dns.NS{Hdr: dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: 300}, Ns: "ns1.example.com."},
+			&dns.NS{Hdr: dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: 300}, Ns: "ns2.example.com."},
+		)
+		return msg
+	}
+
+	udpServer := &dns.Server{
+		Addr: fmt.Sprintf(":%d", addr.Port),
+		Net:  "udp",
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			msg := new(dns.Msg)
+			msg.SetReply(r)
+			msg.Truncated = true
+			w.WriteMsg(msg)
+		}),
+	}
+	tcpServer := &dns.Server{
+		Addr: fmt.Sprintf(":%d", addr.Port),
+		Net:  "tcp",
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			w.WriteMsg(fullAnswer(r))
+		}),
+	}
+
+	go func() {
+		if err := udpServer.ListenAndServe(); err != nil {
+			t.Logf("mock UDP server error: %v", err)
+		}
+	}()
+	go func() {
+		if err := tcpServer.ListenAndServe(); err != nil {
+			t.Logf("mock TCP server error: %v", err)
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	return fmt.Sprintf("127.0.0.1:%d", addr.Port), func() {
+		udpServer.Shutdown()
+		tcpServer.Shutdown()
+	}
+}
+
+func TestScanner_TruncatedUDPFallsBackToTCP(t *testing.T) {
+	addr, cleanup := startTruncatingDNSServers(t)
+	defer cleanup()
+
+	s := NewScanner(&Config{Nameservers: addr, Threads: 1})
+
+	m := s.newQuery("example.com", dns.TypeNS)
+	r, err := s.exchange(m, s.nameserver)
+	if err != nil {
+		t.Fatalf("exchange() error = %v", err)
+	}
+	if r.Truncated {
+		t.Error("expected the TCP retry's untruncated response, got Truncated = true")
+	}
+	if len(r.Answer) != 2 {
+		t.Errorf("len(r.Answer) = %d, want 2 NS records recovered over TCP", len(r.Answer))
+	}
+}
+
+func TestScanner_NewQueryAdvertisesEDNS0BufferSize(t *testing.T) {
+	s := NewScanner(&Config{Nameservers: "127.0.0.1:53", Threads: 1})
+
+	m := s.newQuery("example.com", dns.TypeNS)
+	opt := m.IsEdns0()
+	if opt == nil {
+		t.Fatal("expected newQuery to attach an EDNS0 OPT record by default")
+	}
+	if opt.UDPSize() != 4096 {
+		t.Errorf("UDPSize() = %d, want 4096 so large answers are less likely to truncate over UDP", opt.UDPSize())
+	}
+}
+
+func TestScanner_ForceTCP(t *testing.T) {
+	addr, cleanup := startTruncatingDNSServers(t)
+	defer cleanup()
+
+	s := NewScanner(&Config{Nameservers: addr, ForceTCP: true, Threads: 1})
+
+	ep := s.endpoints[0]
+	ct, ok := ep.transport.(*clientTransport)
+	if !ok {
+		t.Fatalf("expected a *clientTransport, got %T", ep.transport)
+	}
+	if ct.client.Net != "tcp" {
+		t.Errorf("client.Net = %q, want %q when ForceTCP is set", ct.client.Net, "tcp")
+	}
+}
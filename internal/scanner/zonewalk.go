@@ -0,0 +1,149 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ducksify/godnstwist/internal/fuzzer"
+
+	"github.com/miekg/dns"
+)
+
+// maxZoneWalkSteps bounds the NSEC chain walk so a misbehaving or
+// adversarial authority can't keep the scanner walking forever.
+const maxZoneWalkSteps = 10000
+
+// ZoneWalk enumerates sibling names that exist in a DNSSEC-signed zone by
+// following the NSEC "next owner name" chain, or, for NSEC3-signed zones,
+// by attempting to reverse the hashed owner names against dictionary. It is
+// aggressive (many queries against the authority) and is only invoked when
+// Config.ZoneWalk is set.
+//
+// The returned domains carry Fuzzer: "zone-walk" and only the Domain field
+// populated; callers run them back through Scan like any other candidate.
+func (s *Scanner) ZoneWalk(zone string, dictionary []string) []*fuzzer.Domain {
+	zone = dns.Fqdn(zone)
+
+	if nsec3Params, ok := s.lookupNSEC3Params(zone); ok {
+		return s.zoneWalkNSEC3(zone, nsec3Params, dictionary)
+	}
+
+	return s.zoneWalkNSEC(zone)
+}
+
+// lookupNSEC3Params probes the zone apex for an NSEC3PARAM record to decide
+// whether the zone uses NSEC3 (hashed, requires dictionary reversal) rather
+// than plain NSEC (walkable in the clear).
+func (s *Scanner) lookupNSEC3Params(zone string) (*dns.NSEC3PARAM, bool) {
+	m := s.dnssecQuery(zone, dns.TypeNSEC3PARAM)
+	r, err := s.exchange(m, s.nameserver)
+	if err != nil || r == nil {
+		return nil, false
+	}
+
+	for _, rr := range r.Answer {
+		if p, ok := rr.(*dns.NSEC3PARAM); ok {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// zoneWalkNSEC follows the NSEC "next owner name" chain starting from a
+// deliberately nonexistent name under zone, collecting every owner name
+// until the chain wraps back to its starting point.
+func (s *Scanner) zoneWalkNSEC(zone string) []*fuzzer.Domain {
+	var discovered []*fuzzer.Domain
+	seen := make(map[string]bool)
+
+	cursor := "zone-walk-probe." + zone
+	for i := 0; i < maxZoneWalkSteps; i++ {
+		m := s.dnssecQuery(cursor, dns.TypeA)
+		r, err := s.exchange(m, s.nameserver)
+		if err != nil || r == nil {
+			break
+		}
+
+		next := ""
+		for _, rr := range append(r.Answer, r.Ns...) {
+			if nsec, ok := rr.(*dns.NSEC); ok {
+				next = nsec.NextDomain
+				break
+			}
+		}
+		if next == "" || seen[next] {
+			break
+		}
+		seen[next] = true
+
+		if next != cursor && next != zone && strings.HasSuffix(next, zone) {
+			discovered = append(discovered, &fuzzer.Domain{
+				Fuzzer: "zone-walk",
+				Domain: strings.TrimSuffix(next, "."),
+				DNS:    make(map[string][]string),
+				Banner: make(map[string]string),
+				Whois:  make(map[string]string),
+				LSH:    make(map[string]int),
+			})
+		}
+
+		// The chain wrapped around to the zone apex: every owner name has
+		// been visited.
+		if next == zone {
+			break
+		}
+		cursor = next
+	}
+
+	return discovered
+}
+
+// zoneWalkNSEC3 collects NSEC3 hashed owner names seen while probing the
+// zone and attempts to reverse each one against dictionary, per RFC 5155,
+// using the salt and iteration count advertised in params.
+func (s *Scanner) zoneWalkNSEC3(zone string, params *dns.NSEC3PARAM, dictionary []string) []*fuzzer.Domain {
+	hashes := make(map[string]bool)
+
+	probes := append([]string{"zone-walk-probe." + zone}, dictionary...)
+	for _, label := range probes {
+		name := label
+		if !strings.HasSuffix(name, zone) {
+			name = fmt.Sprintf("%s.%s", label, zone)
+		}
+
+		m := s.dnssecQuery(name, dns.TypeA)
+		r, err := s.exchange(m, s.nameserver)
+		if err != nil || r == nil {
+			continue
+		}
+
+		for _, rr := range append(r.Answer, r.Ns...) {
+			if nsec3, ok := rr.(*dns.NSEC3); ok {
+				hashes[strings.ToUpper(nsec3.NextDomain)] = true
+				if owner := strings.SplitN(nsec3.Hdr.Name, ".", 2)[0]; owner != "" {
+					hashes[strings.ToUpper(owner)] = true
+				}
+			}
+		}
+	}
+
+	var discovered []*fuzzer.Domain
+	for _, candidate := range dictionary {
+		owner := dns.Fqdn(fmt.Sprintf("%s.%s", candidate, strings.TrimSuffix(zone, ".")))
+		hash := dns.HashName(owner, params.Hash, params.Iterations, params.Salt)
+		if !hashes[hash] {
+			continue
+		}
+
+		discovered = append(discovered, &fuzzer.Domain{
+			Fuzzer: "zone-walk",
+			Domain: fmt.Sprintf("%s.%s", candidate, strings.TrimSuffix(zone, ".")),
+			DNS:    make(map[string][]string),
+			Banner: make(map[string]string),
+			Whois:  make(map[string]string),
+			LSH:    make(map[string]int),
+		})
+	}
+
+	return discovered
+}
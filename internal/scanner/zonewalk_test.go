@@ -0,0 +1,180 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// startNSECZoneServer serves a tiny hand-built NSEC chain for example.com:
+// apex -> mail.example.com. -> www.example.com. -> apex (wrapped).
+func startNSECZoneServer(t *testing.T) (string, func()) {
+	chain := map[string]string{
+		"zone-walk-probe.example.com.": "mail.example.com.",
+		"mail.example.com.":            "www.example.com.",
+		"www.example.com.":             "example.com.",
+	}
+
+	server := &dns.Server{
+		Addr: ":0",
+		Net:  "udp",
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			msg := dns.Msg{}
+			msg.SetReply(r)
+			msg.Authoritative = true
+
+			name := r.Question[0].Name
+			if next, ok := chain[name]; ok {
+				msg.Ns = append(msg.Ns, &dns.NSEC{
+					Hdr:        dns.RR_Header{Name: name, Rrtype: dns.TypeNSEC, Class: dns.ClassINET, Ttl: 300},
+					NextDomain: next,
+				})
+			}
+
+			w.WriteMsg(&msg)
+		}),
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil {
+			t.Logf("mock NSEC server error: %v", err)
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	addr := server.PacketConn.LocalAddr().String()
+
+	return addr, func() { server.Shutdown() }
+}
+
+func TestScanner_ZoneWalk_NSEC(t *testing.T) {
+	addr, cleanup := startNSECZoneServer(t)
+	defer cleanup()
+
+	s := NewScanner(&Config{Nameservers: addr, Threads: 1})
+
+	discovered := s.ZoneWalk("example.com", nil)
+
+	names := make(map[string]bool)
+	for _, d := range discovered {
+		if d.Fuzzer != "zone-walk" {
+			t.Errorf("Fuzzer = %q, want %q", d.Fuzzer, "zone-walk")
+		}
+		names[d.Domain] = true
+	}
+
+	for _, want := range []string{"mail.example.com", "www.example.com"} {
+		if !names[want] {
+			t.Errorf("expected zone walk to discover %q, got %v", want, names)
+		}
+	}
+}
+
+// startNSEC3ZoneServer serves NSEC3PARAM at the apex plus an NSEC3 record
+// covering "www.example.com." for every query, so that a zone walk can
+// reverse it out of the dictionary.
+func startNSEC3ZoneServer(t *testing.T) (string, func()) {
+	const (
+		salt = "AABBCCDD"
+		iter = 1
+	)
+	// The single NSEC3 record below covers the gap between "mail" (its own
+	// hashed owner name) and "www" (its NextDomain hash); a correct reversal
+	// must recover both dictionary words from the one record.
+	mailHash := dns.HashName("mail.example.com.", dns.SHA1, iter, salt)
+	wwwHash := dns.HashName("www.example.com.", dns.SHA1, iter, salt)
+
+	server := &dns.Server{
+		Addr: ":0",
+		Net:  "udp",
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			msg := dns.Msg{}
+			msg.SetReply(r)
+			msg.Authoritative = true
+
+			q := r.Question[0]
+			if q.Qtype == dns.TypeNSEC3PARAM {
+				msg.Answer = append(msg.Answer, &dns.NSEC3PARAM{
+					Hdr:        dns.RR_Header{Name: q.Name, Rrtype: dns.TypeNSEC3PARAM, Class: dns.ClassINET, Ttl: 300},
+					Hash:       dns.SHA1,
+					Iterations: iter,
+					SaltLength: uint8(len(salt) / 2),
+					Salt:       salt,
+				})
+			} else {
+				msg.Ns = append(msg.Ns, &dns.NSEC3{
+					Hdr:        dns.RR_Header{Name: mailHash + ".example.com.", Rrtype: dns.TypeNSEC3, Class: dns.ClassINET, Ttl: 300},
+					Hash:       dns.SHA1,
+					Iterations: iter,
+					SaltLength: uint8(len(salt) / 2),
+					Salt:       salt,
+					HashLength: 20,
+					NextDomain: wwwHash,
+				})
+			}
+
+			w.WriteMsg(&msg)
+		}),
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil {
+			t.Logf("mock NSEC3 server error: %v", err)
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	addr := server.PacketConn.LocalAddr().String()
+
+	return addr, func() { server.Shutdown() }
+}
+
+func TestScanner_ZoneWalk_NSEC3(t *testing.T) {
+	addr, cleanup := startNSEC3ZoneServer(t)
+	defer cleanup()
+
+	s := NewScanner(&Config{Nameservers: addr, Threads: 1})
+
+	discovered := s.ZoneWalk("example.com", []string{"www", "mail", "ftp"})
+
+	names := make(map[string]bool)
+	for _, d := range discovered {
+		if d.Fuzzer != "zone-walk" {
+			t.Errorf("Fuzzer = %q, want %q", d.Fuzzer, "zone-walk")
+		}
+		names[d.Domain] = true
+	}
+
+	for _, want := range []string{"mail.example.com", "www.example.com"} {
+		if !names[want] {
+			t.Errorf("expected zone walk to reverse %q via NSEC3, got %v", want, discovered)
+		}
+	}
+	if names["ftp.example.com"] {
+		t.Errorf("did not expect ftp.example.com to be reversed, got %v", discovered)
+	}
+}
+
+func TestScanner_ZoneWalk_NoDNSSEC(t *testing.T) {
+	server := &dns.Server{
+		Addr: ":0",
+		Net:  "udp",
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			msg := dns.Msg{}
+			msg.SetReply(r)
+			w.WriteMsg(&msg)
+		}),
+	}
+	go server.ListenAndServe()
+	time.Sleep(100 * time.Millisecond)
+	addr := server.PacketConn.LocalAddr().String()
+	defer server.Shutdown()
+
+	s := NewScanner(&Config{Nameservers: addr, Threads: 1})
+
+	discovered := s.ZoneWalk("example.com", nil)
+	if len(discovered) != 0 {
+		t.Errorf("expected no discovered names against an unsigned zone, got %v", discovered)
+	}
+}
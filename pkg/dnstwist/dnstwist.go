@@ -1,9 +1,12 @@
 package dnstwist
 
 import (
+	"bufio"
 	"fmt"
+	"os"
 	"strings"
 
+	"github.com/ducksify/godnstwist/internal/ct"
 	"github.com/ducksify/godnstwist/internal/formatter"
 	"github.com/ducksify/godnstwist/internal/fuzzer"
 	"github.com/ducksify/godnstwist/internal/scanner"
@@ -29,6 +32,75 @@ func containsNonASCII(s string) bool {
 	return false
 }
 
+// domainPart extracts the registrable name without its TLD from a domain
+// such as "example.com", i.e. "example". Used to scope CT log queries.
+func domainPart(domain string) string {
+	parts := strings.Split(domain, ".")
+	if len(parts) < 2 {
+		return domain
+	}
+	return parts[len(parts)-2]
+}
+
+// readDictionaryFile reads newline-separated candidate words from path, used
+// as NSEC3 hash-reversal plaintexts for zone walking. An empty path yields
+// no words rather than an error, since zone walking still benefits from the
+// plain-NSEC path without a dictionary.
+func readDictionaryFile(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		words = append(words, word)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return words, nil
+}
+
+// validateNameservers rejects an Options.Nameservers or Options.Transports
+// entry with an unsupported scheme up front, instead of only discovering it
+// the first time a query is issued. Nameservers entries may carry the
+// multi-resolver "|weight="/"|ecs="/"|strategy=" attribute syntax (see
+// scanner.parseResolvers); only the server part before the first "|" is a
+// nameserver address.
+func validateNameservers(options Options) error {
+	for _, raw := range options.Transports {
+		if err := scanner.ValidateNameserver(strings.TrimSpace(raw)); err != nil {
+			return fmt.Errorf("invalid transport %q: %w", raw, err)
+		}
+	}
+
+	if options.Nameservers == "" {
+		return nil
+	}
+	for _, entry := range strings.Split(options.Nameservers, ",") {
+		server := strings.TrimSpace(strings.SplitN(entry, "|", 2)[0])
+		if server == "" {
+			continue
+		}
+		if err := scanner.ValidateNameserver(server); err != nil {
+			return fmt.Errorf("invalid nameserver %q: %w", entry, err)
+		}
+	}
+	return nil
+}
+
 // Options represents the configuration options for the domain permutation engine
 
 // New creates a new domain permutation engine with the given options
@@ -45,8 +117,21 @@ func New(options Options) (*Engine, error) {
 		return nil, fmt.Errorf("number of threads must be greater than zero")
 	}
 
+	// Options.Resolver replaces the entire nameserver-string DNS path, so
+	// there's nothing to validate or parse.
+	if options.Resolver == nil {
+		if err := validateNameservers(options); err != nil {
+			return nil, err
+		}
+	}
+
 	// Initialize fuzzer
-	f := fuzzer.NewFuzzer(options.Domain)
+	var f *fuzzer.Fuzzer
+	if options.PSLFile != "" {
+		f = fuzzer.NewFuzzerWithPSLFile(options.Domain, options.PSLFile)
+	} else {
+		f = fuzzer.NewFuzzer(options.Domain)
+	}
 	if f == nil {
 		return nil, fmt.Errorf("invalid domain name: %s", options.Domain)
 	}
@@ -58,6 +143,10 @@ func New(options Options) (*Engine, error) {
 		f.SetTLDFile(tldFiles)
 	}
 
+	if options.HomoglyphScript != "" {
+		f.SetHomoglyphScript(options.HomoglyphScript)
+	}
+
 	// Initialize scanner
 	// Enable NS lookups when filtering and either NS is explicitly requested
 	// or when using the default OR logic (no explicit selector).
@@ -66,17 +155,33 @@ func New(options Options) (*Engine, error) {
 	defaultOR := !(useNSSelector || useASelector)
 	nsNeeded := (options.Registered || options.Unregistered) && (useNSSelector || defaultOR)
 	scannerConfig := &scanner.Config{
-		All:         options.All,
-		Banners:     options.Banners,
-		GeoIP:       options.GeoIP,
-		LSH:         options.LSH,
-		MXCheck:     options.MXCheck,
-		NSCheck:     options.NSCheck || nsNeeded,
-		Nameservers: options.Nameservers,
-		PHash:       options.PHash,
-		Screenshots: options.Screenshots,
-		UserAgent:   options.UserAgent,
-		Threads:     options.Threads,
+		All:                options.All,
+		Banners:            options.Banners,
+		DNSSEC:             options.DNSSEC,
+		GeoIP:              options.GeoIP,
+		LSH:                options.LSH,
+		MXCheck:            options.MXCheck,
+		EmailAuth:          options.EmailAuth,
+		DKIMSelectors:      options.DKIMSelectors,
+		NSCheck:            options.NSCheck || nsNeeded,
+		Nameservers:        options.Nameservers,
+		PHash:              options.PHash,
+		Screenshots:        options.Screenshots,
+		UserAgent:          options.UserAgent,
+		Threads:            options.Threads,
+		ZoneWalk:           options.ZoneWalk,
+		Transports:         options.Transports,
+		TransportFallback:  options.TransportFallback,
+		ForceTCP:           options.ForceTCP,
+		QueryStrategy:      options.QueryStrategy,
+		ResolverStrategy:   options.ResolverStrategy,
+		ParallelResolvers:  options.ParallelResolvers,
+		DisableCache:       options.DisableCache,
+		CacheTTLCap:        options.CacheTTLCap,
+		CacheSize:          options.CacheSize,
+		QueryAuthoritative: options.QueryAuthoritative,
+		EDNS:               scanner.EDNSConfig{ClientSubnet: options.ClientSubnet},
+		Resolver:           options.Resolver,
 	}
 
 	s := scanner.NewScanner(scannerConfig)
@@ -98,8 +203,26 @@ func (e *Engine) generate() ([]Result, error) {
 		return nil, fmt.Errorf("failed to generate permutations: %v", err)
 	}
 
+	if e.options.CT {
+		names, err := ct.NewClient().Query(domainPart(e.options.Domain))
+		if err != nil {
+			return nil, fmt.Errorf("failed to query Certificate Transparency logs: %v", err)
+		}
+		e.fuzzer.AddExternal("ct", names)
+	}
+
+	candidates := e.fuzzer.Domains()
+
+	if e.options.ZoneWalk {
+		wordlist, err := readDictionaryFile(e.options.Dictionary)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read zone-walk dictionary: %v", err)
+		}
+		candidates = append(candidates, e.scanner.ZoneWalk(e.options.Domain, wordlist)...)
+	}
+
 	// Scan domains
-	domains := e.scanner.Scan(e.fuzzer.Domains())
+	domains := e.scanner.Scan(candidates)
 
 	// Convert to results
 	results := make([]Result, 0, len(domains))
@@ -107,16 +230,17 @@ func (e *Engine) generate() ([]Result, error) {
 		// Determine registration condition
 		recordType := strings.ToUpper(strings.TrimSpace(e.options.RegisteredBy))
 		hasA := len(domain.DNS["A"]) > 0
+		hasAAAA := len(domain.DNS["AAAA"]) > 0
 		hasNS := len(domain.DNS["NS"]) > 0
 		var isRegistered bool
 		switch recordType {
 		case "A":
-			isRegistered = hasA
+			isRegistered = hasA || hasAAAA
 		case "NS":
 			isRegistered = hasNS
 		default:
-			// Default: A OR NS
-			isRegistered = hasA || hasNS
+			// Default: A/AAAA OR NS
+			isRegistered = hasA || hasAAAA || hasNS
 		}
 
 		// Filter based on registered/unregistered flags
@@ -130,23 +254,30 @@ func (e *Engine) generate() ([]Result, error) {
 		// Use the Cyrillic field that was already set by the fuzzer
 		isCyrillic := domain.Cyrillic
 
-		// Only set Punycode for non-ASCII domains
-		var punycode string
+		// Only set Punycode/Unicode for non-ASCII domains
+		var punycode, unicodeForm string
 		if containsNonASCII(domain.Domain) {
 			punycode = domain.Punycode
+			unicodeForm = domain.Domain
 		}
 
 		results = append(results, Result{
-			Fuzzer:   domain.Fuzzer,
-			Domain:   domain.Domain,
-			Punycode: punycode,
-			Cyrillic: isCyrillic,
-			DNS:      domain.DNS,
-			GeoIP:    domain.GeoIP,
-			Banner:   domain.Banner,
-			Whois:    domain.Whois,
-			LSH:      domain.LSH,
-			PHash:    domain.PHash,
+			Fuzzer:        domain.Fuzzer,
+			Domain:        domain.Domain,
+			Punycode:      punycode,
+			Unicode:       unicodeForm,
+			Cyrillic:      isCyrillic,
+			DNS:           domain.DNS,
+			GeoIP:         domain.GeoIP,
+			Banner:        domain.Banner,
+			Whois:         domain.Whois,
+			LSH:           domain.LSH,
+			PHash:         domain.PHash,
+			DNSSEC:        domain.DNSSEC,
+			EDNS:          domain.EDNS,
+			EmailAuth:     domain.EmailAuth,
+			ResolvedBy:    domain.ResolvedBy,
+			Confusability: domain.Confusability,
 		})
 	}
 
@@ -159,16 +290,21 @@ func (e *Engine) format(results []Result) (string, error) {
 	domains := make([]*fuzzer.Domain, len(results))
 	for i, r := range results {
 		domains[i] = &fuzzer.Domain{
-			Fuzzer:   r.Fuzzer,
-			Domain:   r.Domain,
-			Punycode: r.Punycode,
-			Cyrillic: r.Cyrillic,
-			DNS:      r.DNS,
-			GeoIP:    r.GeoIP,
-			Banner:   r.Banner,
-			Whois:    r.Whois,
-			LSH:      r.LSH,
-			PHash:    r.PHash,
+			Fuzzer:        r.Fuzzer,
+			Domain:        r.Domain,
+			Punycode:      r.Punycode,
+			Cyrillic:      r.Cyrillic,
+			DNS:           r.DNS,
+			GeoIP:         r.GeoIP,
+			Banner:        r.Banner,
+			Whois:         r.Whois,
+			LSH:           r.LSH,
+			PHash:         r.PHash,
+			DNSSEC:        r.DNSSEC,
+			EDNS:          r.EDNS,
+			EmailAuth:     r.EmailAuth,
+			ResolvedBy:    r.ResolvedBy,
+			Confusability: r.Confusability,
 		}
 
 	}
@@ -187,3 +323,14 @@ func (e *Engine) format(results []Result) (string, error) {
 func (e *Engine) GetResults() (Results, error) {
 	return e.generate()
 }
+
+// Metrics returns the scanner's inflight-dedup, connection-reuse, and
+// answer-cache counters for the most recent scan.
+func (e *Engine) Metrics() scanner.Metrics {
+	return e.scanner.Metrics()
+}
+
+// ClearCache empties the scanner's in-process DNS answer cache.
+func (e *Engine) ClearCache() {
+	e.scanner.ClearCache()
+}
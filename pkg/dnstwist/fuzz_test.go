@@ -0,0 +1,160 @@
+package dnstwist
+
+import "testing"
+
+// FuzzOptionsNew exercises New with arbitrary Options field combinations
+// built around TestOptions_Validation's table, to catch panics in
+// validateNameservers and the rest of New's setup path on malformed
+// Nameservers/Transports strings (unknown schemes, malformed sdns:// stamps,
+// garbage attribute suffixes).
+func FuzzOptionsNew(f *testing.F) {
+	seeds := []string{
+		"",
+		"8.8.8.8",
+		"udp://8.8.8.8:53",
+		"tls://1.1.1.1:853",
+		"https://dns.google/dns-query",
+		"sdns://AQcAAAA",
+		"sdns://AQcAAAA|weight=2",
+		"ftp://8.8.8.8:53",
+		"8.8.8.8|ecs=203.0.113.0/24|strategy=useIPv4",
+		",,,",
+		"://",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, nameservers string) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("New(Nameservers: %q) panicked: %v", nameservers, r)
+			}
+		}()
+
+		_, _ = New(Options{Domain: "example.com", Threads: 1, Nameservers: nameservers})
+	})
+}
+
+// FuzzResultsFormat exercises Results.Format and toDomain with adversarial
+// Result contents (nil DNS maps, very long RR strings, non-UTF8 bytes) and
+// arbitrary format names, to catch panics beyond the happy-path shapes
+// covered by TestResults_FormatAllFormats.
+func FuzzResultsFormat(f *testing.F) {
+	type seed struct {
+		format string
+		domain string
+		rr     string
+	}
+	seeds := []seed{
+		{"json", "example.com", "93.184.216.34"},
+		{"csv", "example.com", "93.184.216.34"},
+		{"list", "example.com", "93.184.216.34"},
+		{"cli", "example.com", "93.184.216.34"},
+		{"invalid", "example.com", "93.184.216.34"},
+		{"", "", ""},
+		{"json", "\xff\xfe invalid utf-8", "\xff\xfe invalid utf-8"},
+	}
+	for _, s := range seeds {
+		f.Add(s.format, s.domain, s.rr)
+	}
+
+	f.Fuzz(func(t *testing.T, format, domain, rr string) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("Format(%q) with domain %q, rr %q panicked: %v", format, domain, rr, r)
+			}
+		}()
+
+		results := Results{
+			{
+				Fuzzer: "fuzz",
+				Domain: domain,
+				DNS:    map[string][]string{"A": {rr}},
+			},
+			{
+				Fuzzer: "nilmaps",
+				Domain: domain,
+			},
+		}
+		_ = results.Format(format)
+	})
+}
+
+// FuzzNew exercises dnstwist.New with arbitrary domain strings, including
+// malformed IDN/Punycode input, to catch panics in the fuzzer/IDNA chain
+// that New triggers while building the permutation engine.
+func FuzzNew(f *testing.F) {
+	seeds := []string{
+		"example.com",
+		"xn--e1aybc.xn--p1ai", // "test.рф" in Punycode
+		"тест.рф",             // Cyrillic, not yet Punycode-encoded
+		"xn--zzzzzz.com",      // not a valid Punycode label
+		"xn--.com",            // empty Punycode payload
+		"",
+		"invalid domain",
+		"a.b.c.d.e.f.g.h.i.j.k", // deep label nesting
+		"💩.com",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, domain string) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("New(%q) panicked: %v", domain, r)
+			}
+		}()
+
+		_, _ = New(Options{Domain: domain, Threads: 1})
+	})
+}
+
+// FuzzContainsCyrillic exercises containsCyrillic with arbitrary (including
+// invalid UTF-8) input.
+func FuzzContainsCyrillic(f *testing.F) {
+	seeds := []string{
+		"example.com",
+		"тест.рф",
+		"\xff\xfe invalid utf-8",
+		"",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("containsCyrillic(%q) panicked: %v", s, r)
+			}
+		}()
+
+		containsCyrillic(s)
+	})
+}
+
+// FuzzContainsNonASCII exercises containsNonASCII with arbitrary (including
+// invalid UTF-8) input.
+func FuzzContainsNonASCII(f *testing.F) {
+	seeds := []string{
+		"example.com",
+		"münchen.de",
+		"\xff\xfe invalid utf-8",
+		"",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("containsNonASCII(%q) panicked: %v", s, r)
+			}
+		}()
+
+		containsNonASCII(s)
+	})
+}
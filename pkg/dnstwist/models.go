@@ -1,7 +1,15 @@
 package dnstwist
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
 	"sync"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
 
 	"github.com/ducksify/godnstwist/internal/formatter"
 	"github.com/ducksify/godnstwist/internal/fuzzer"
@@ -18,6 +26,10 @@ type Options struct {
 	// Banners determines HTTP and SMTP service banners
 	Banners bool
 
+	// DNSSEC sets the AD flag and DO bit on outgoing queries and collects
+	// DNSKEY/DS/RRSIG/NSEC records to determine each domain's DNSSEC posture
+	DNSSEC bool
+
 	// Dictionary generates more domains using dictionary file
 	Dictionary string
 
@@ -39,6 +51,16 @@ type Options struct {
 	// MXCheck checks if MX host can be used to intercept emails
 	MXCheck bool
 
+	// EmailAuth looks up and parses SPF/DMARC (and, with DKIMSelectors,
+	// DKIM) TXT records for domains that resolved MX records, to triage
+	// how exposed a lookalike is for outbound mail spoofing.
+	EmailAuth bool
+
+	// DKIMSelectors additionally queries "<selector>._domainkey.<domain>"
+	// for each listed selector when EmailAuth is set. DKIM has no discovery
+	// mechanism, so candidates must be supplied (e.g. "google", "default").
+	DKIMSelectors []string
+
 	// NSCheck checks for nameserver records
 	NSCheck bool
 
@@ -51,6 +73,12 @@ type Options struct {
 	// Unregistered shows only unregistered domain names
 	Unregistered bool
 
+	// RegisteredBy selects which DNS record type determines registration
+	// for Registered/Unregistered filtering: "A" requires an A record,
+	// "NS" requires an NS record, and "" (the default) treats a domain as
+	// registered if either is present.
+	RegisteredBy string
+
 	// PHash renders web pages and evaluates visual similarity
 	PHash bool
 
@@ -69,23 +97,162 @@ type Options struct {
 	// TLD swaps TLD for the original domain from files (multiple files supported)
 	TLD []string
 
-	// Nameservers specifies DNS or DoH servers to query (comma-separated)
+	// Nameservers specifies DNS servers to query (comma-separated). Each
+	// entry defaults to plain UDP "host:port" but may be prefixed with a
+	// scheme to select another transport: "udp://", "tcp://", "tls://"
+	// (DoT), "quic://" (DoQ), "https://" (DoH), or "sdns://" (a DNSCrypt
+	// stamp).
 	Nameservers string
 
+	// Transports is an ordered list of nameserver endpoints (e.g.
+	// "tls://1.1.1.1:853", "https://dns.google/dns-query", "sdns://..."),
+	// tried in order when TransportFallback is set. Takes precedence over
+	// Nameservers.
+	Transports []string
+
+	// TransportFallback retries each subsequent Transports (or Nameservers)
+	// entry on timeout/error instead of only ever using the first one.
+	TransportFallback bool
+
+	// ForceTCP skips UDP entirely, issuing every plain/unencrypted query
+	// over TCP.
+	ForceTCP bool
+
+	// HomoglyphScript restricts the homoglyph fuzzer to confusables from a
+	// single Unicode script (e.g. "cyrillic", "greek", "armenian"). An empty
+	// string considers confusables from every script.
+	HomoglyphScript string
+
+	// ZoneWalk enables the aggressive NSEC/NSEC3 zone-walking fuzzer, which
+	// harvests real sibling names from the target's DNSSEC-signed zone
+	// instead of generating permutations. Uses Dictionary as the plaintext
+	// wordlist for NSEC3 hash reversal.
+	ZoneWalk bool
+
+	// CT enables Certificate Transparency log ingestion as a discovery
+	// source: certificates whose CN/SAN reference the target's domain-part
+	// are added as "ct" candidates alongside the generated permutations.
+	CT bool
+
 	// UserAgent sets the User-Agent string
 	UserAgent string
+
+	// ResolverStrategy controls how A/MX/NS queries are aggregated when
+	// Nameservers configures more than one resolver (comma-separated,
+	// optionally with "|weight=", "|ecs=", "|strategy=" attributes per
+	// entry): "first" (default) returns the first authoritative answer,
+	// "all" merges every resolver's answers, and "majority" returns the
+	// answer set agreed on by the highest total resolver weight.
+	ResolverStrategy string
+
+	// ParallelResolvers caps how many resolvers the "first" strategy fans a
+	// single query out to at once, preferring the healthiest ones. Defaults
+	// to 2 when zero or negative. Ignored by "all" and "majority".
+	ParallelResolvers int
+
+	// DisableCache turns off the scanner's in-process DNS answer cache,
+	// which is on by default. Set true for one-shot scans that want every
+	// query to hit the wire.
+	DisableCache bool
+
+	// CacheTTLCap bounds how long any single cached answer is kept,
+	// regardless of the TTL/SOA minimum the nameserver reported. Defaults
+	// to 60s when zero.
+	CacheTTLCap time.Duration
+
+	// CacheSize bounds how many answers the DNS answer cache holds at once,
+	// evicting the least recently used entry once exceeded. Defaults to
+	// 4096 when zero or negative; raise it for large scans (--threads) that
+	// would otherwise thrash the cache across many distinct zones.
+	CacheSize int
+
+	// QueryAuthoritative discovers and queries each domain's authoritative
+	// nameserver directly instead of going through Nameservers, reducing
+	// false negatives from resolver-side NXDOMAIN caching/rate-limiting and
+	// surfacing wildcard A records a recursive resolver may mask.
+	QueryAuthoritative bool
+
+	// QueryStrategy selects which address family is queried and considered
+	// for registration classification: "useIPv4" issues only A queries,
+	// "useIPv6" issues only AAAA queries, and "" or "useIP" (the default)
+	// issues both.
+	QueryStrategy string
+
+	// ClientSubnet attaches an EDNS0 Client Subnet option (RFC 7871) in
+	// CIDR form (e.g. "203.0.113.0/24") to every outgoing query, letting a
+	// resolver tailor its answer to that network - useful for surfacing
+	// regionally-targeted phishing infrastructure that serves different A
+	// records per ECS scope. A per-entry "|ecs=" attribute in Nameservers
+	// overrides this for that one resolver.
+	ClientSubnet string
+
+	// Resolver, when set, replaces the entire built-in DNS path
+	// (Nameservers, Transports, QueryAuthoritative, ResolverStrategy, and
+	// their validation) with a caller-provided DNS backend. Use
+	// CachingResolver or MultiResolver to wrap an existing implementation,
+	// or implement Resolver directly to integrate with another resolver
+	// stack, add rate limiting, or record/replay DNS for reproducible
+	// scans.
+	Resolver Resolver
+
+	// PSLFile overrides the embedded golang.org/x/net/publicsuffix snapshot
+	// with a Public Suffix List file in the format published at
+	// https://publicsuffix.org/list/public_suffix_list.dat. Used to compute
+	// the domain's eTLD+1 for fuzzing and TLD swapping. Empty uses the
+	// embedded list.
+	PSLFile string
 }
 
 // Result represents a single domain permutation result
 type Result struct {
-	Fuzzer string              `json:"fuzzer"`
-	Domain string              `json:"domain"`
-	DNS    map[string][]string `json:"dns,omitempty"`
-	GeoIP  string              `json:"geoip,omitempty"`
-	Banner map[string]string   `json:"banner,omitempty"`
-	Whois  map[string]string   `json:"whois,omitempty"`
-	LSH    map[string]int      `json:"lsh,omitempty"`
-	PHash  int                 `json:"phash,omitempty"`
+	Fuzzer    string              `json:"fuzzer"`
+	Domain    string              `json:"domain"`
+	DNS       map[string][]string `json:"dns,omitempty"`
+	GeoIP     string              `json:"geoip,omitempty"`
+	Banner    map[string]string   `json:"banner,omitempty"`
+	Whois     map[string]string   `json:"whois,omitempty"`
+	LSH       map[string]int      `json:"lsh,omitempty"`
+	PHash     int                 `json:"phash,omitempty"`
+	DNSSEC    *fuzzer.DNSSECInfo  `json:"dnssec,omitempty"`
+	EDNS      *fuzzer.EDNSInfo    `json:"edns,omitempty"`
+	EmailAuth *fuzzer.EmailAuth   `json:"email_auth,omitempty"`
+	// Punycode is the ASCII-compatible (A-label) form of Domain when it
+	// contains non-ASCII code points, e.g. from the homoglyph fuzzer.
+	Punycode string `json:"punycode,omitempty"`
+	// Unicode is the NFC-normalized Unicode (U-label) form of Domain when it
+	// contains non-ASCII code points, i.e. the decoded counterpart of
+	// Punycode.
+	Unicode string `json:"unicode,omitempty"`
+	// Cyrillic is true when Domain contains a Cyrillic code point.
+	Cyrillic bool `json:"cyrillic,omitempty"`
+	// ResolvedBy identifies the resolver(s) that produced the A record
+	// answer, when Nameservers configures more than one resolver.
+	ResolvedBy string `json:"resolved_by,omitempty"`
+	// Confusability scores how visually confusable Domain is with the
+	// original domain on a 0-100 scale, per the UTS #39 skeleton algorithm.
+	// See fuzzer.Domain.Confusability for the full explanation.
+	Confusability int `json:"confusability"`
+}
+
+// PublicSuffix returns the eTLD of Domain (e.g. "co.uk" for
+// "example.co.uk"), per the embedded golang.org/x/net/publicsuffix list.
+// Always uses the embedded list, even if the Engine was built with
+// Options.PSLFile set.
+func (r *Result) PublicSuffix() string {
+	suffix, _ := publicsuffix.PublicSuffix(r.Domain)
+	return suffix
+}
+
+// RegistrableDomain returns the eTLD+1 of Domain (e.g. "example.co.uk" for
+// "www.example.co.uk"), i.e. the public suffix plus the one label that was
+// actually registered. Returns "" if Domain is itself a public suffix or
+// otherwise has no eTLD+1.
+func (r *Result) RegistrableDomain() string {
+	etld1, err := publicsuffix.EffectiveTLDPlusOne(r.Domain)
+	if err != nil {
+		return ""
+	}
+	return etld1
 }
 
 // GetARecords returns A records for the domain
@@ -96,6 +263,14 @@ func (r *Result) GetARecords() []string {
 	return r.DNS["A"]
 }
 
+// GetAAAARecords returns AAAA records for the domain
+func (r *Result) GetAAAARecords() []string {
+	if r.DNS == nil {
+		return nil
+	}
+	return r.DNS["AAAA"]
+}
+
 // GetMXRecords returns MX records for the domain
 func (r *Result) GetMXRecords() []string {
 	if r.DNS == nil {
@@ -117,6 +292,11 @@ func (r *Result) HasARecords() bool {
 	return len(r.GetARecords()) > 0
 }
 
+// HasAAAARecords returns true if the domain has AAAA records
+func (r *Result) HasAAAARecords() bool {
+	return len(r.GetAAAARecords()) > 0
+}
+
 // HasMXRecords returns true if the domain has MX records
 func (r *Result) HasMXRecords() bool {
 	return len(r.GetMXRecords()) > 0
@@ -174,17 +354,36 @@ func (r Results) GetDomainsWithoutARecords() Results {
 	return filtered
 }
 
+// SortByConfusability returns a copy of r sorted by Confusability in
+// descending order, so the permutations that look most dangerous at a
+// glance (pure homoglyph swaps, scoring 100) sort first.
+func (r Results) SortByConfusability() Results {
+	sorted := make(Results, len(r))
+	copy(sorted, r)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Confusability > sorted[j].Confusability
+	})
+	return sorted
+}
+
 // ToDomain converts a Result to a fuzzer.Domain
 func (r *Result) toDomain() *fuzzer.Domain {
 	return &fuzzer.Domain{
-		Fuzzer: r.Fuzzer,
-		Domain: r.Domain,
-		DNS:    r.DNS,
-		GeoIP:  r.GeoIP,
-		Banner: r.Banner,
-		Whois:  r.Whois,
-		LSH:    r.LSH,
-		PHash:  r.PHash,
+		Fuzzer:        r.Fuzzer,
+		Domain:        r.Domain,
+		DNS:           r.DNS,
+		GeoIP:         r.GeoIP,
+		Banner:        r.Banner,
+		Whois:         r.Whois,
+		LSH:           r.LSH,
+		PHash:         r.PHash,
+		DNSSEC:        r.DNSSEC,
+		EDNS:          r.EDNS,
+		EmailAuth:     r.EmailAuth,
+		Punycode:      r.Punycode,
+		Cyrillic:      r.Cyrillic,
+		ResolvedBy:    r.ResolvedBy,
+		Confusability: r.Confusability,
 	}
 }
 
@@ -207,6 +406,71 @@ func (r Results) Format(format string) string {
 	return f.Format(format)
 }
 
+// FormatStream returns a (write, finish) pair for writing Results to w in
+// format incrementally, one at a time, instead of buffering the whole slice
+// the way Format does. Intended for use with Engine.Stream against
+// permutation sets (large --dictionary files, many --tld entries) too big to
+// comfortably hold in memory at once. write must be called once per Result in
+// arrival order; finish completes the output (closing the JSON array,
+// flushing the CSV writer) and must be called exactly once, after the last
+// write.
+//
+// "json" emits a JSON array, writing each element and the separating commas
+// as they arrive and closing the bracket in finish. "csv" writes the header
+// immediately and then one row per write. "list" and anything else ("cli")
+// write one line per Result; unlike Format("cli"), the fuzzer/domain columns
+// are not aligned, since alignment requires knowing every result's width up
+// front.
+func (r Results) FormatStream(w io.Writer, format string) (write func(Result) error, finish func() error) {
+	switch format {
+	case "json":
+		first := true
+		if _, err := io.WriteString(w, "["); err != nil {
+			return func(Result) error { return err }, func() error { return nil }
+		}
+		return func(res Result) error {
+				if !first {
+					if _, err := io.WriteString(w, ","); err != nil {
+						return err
+					}
+				}
+				first = false
+				data, err := json.Marshal(res.toDomain())
+				if err != nil {
+					return err
+				}
+				_, err = w.Write(data)
+				return err
+			}, func() error {
+				_, err := io.WriteString(w, "]")
+				return err
+			}
+
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write(formatter.CSVHeader())
+		return func(res Result) error {
+				cw.Write(formatter.CSVRecord(res.toDomain()))
+				return cw.Error()
+			}, func() error {
+				cw.Flush()
+				return cw.Error()
+			}
+
+	case "list":
+		return func(res Result) error {
+			_, err := fmt.Fprintln(w, res.Domain)
+			return err
+		}, func() error { return nil }
+
+	default: // "cli" and anything else
+		return func(res Result) error {
+			_, err := fmt.Fprintln(w, formatter.CLIRecord(res.toDomain()))
+			return err
+		}, func() error { return nil }
+	}
+}
+
 // Engine represents the domain permutation engine
 type Engine struct {
 	options Options
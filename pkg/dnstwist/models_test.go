@@ -1,6 +1,8 @@
 package dnstwist
 
 import (
+	"bytes"
+	"encoding/json"
 	"reflect"
 	"testing"
 
@@ -55,6 +57,87 @@ func TestOptions_Validation(t *testing.T) {
 			},
 			valid: false,
 		},
+		{
+			name: "plain nameserver valid",
+			options: Options{
+				Domain:      "example.com",
+				Threads:     1,
+				Nameservers: "8.8.8.8:53",
+			},
+			valid: true,
+		},
+		{
+			name: "udp scheme nameserver valid",
+			options: Options{
+				Domain:      "example.com",
+				Threads:     1,
+				Nameservers: "udp://8.8.8.8:53",
+			},
+			valid: true,
+		},
+		{
+			name: "tcp scheme nameserver valid",
+			options: Options{
+				Domain:      "example.com",
+				Threads:     1,
+				Nameservers: "tcp://8.8.8.8:53",
+			},
+			valid: true,
+		},
+		{
+			name: "dot scheme nameserver valid",
+			options: Options{
+				Domain:      "example.com",
+				Threads:     1,
+				Nameservers: "tls://1.1.1.1:853",
+			},
+			valid: true,
+		},
+		{
+			name: "doq scheme nameserver valid",
+			options: Options{
+				Domain:      "example.com",
+				Threads:     1,
+				Nameservers: "quic://dns.adguard.com:853",
+			},
+			valid: true,
+		},
+		{
+			name: "doh scheme nameserver valid",
+			options: Options{
+				Domain:      "example.com",
+				Threads:     1,
+				Nameservers: "https://dns.google/dns-query",
+			},
+			valid: true,
+		},
+		{
+			name: "dnscrypt scheme nameserver valid, per-resolver attributes ignored",
+			options: Options{
+				Domain:      "example.com",
+				Threads:     1,
+				Nameservers: "sdns://AQcAAAAAAAAAAAAFMS4xLjEuMQo|weight=2",
+			},
+			valid: true,
+		},
+		{
+			name: "unknown scheme nameserver invalid",
+			options: Options{
+				Domain:      "example.com",
+				Threads:     1,
+				Nameservers: "ftp://8.8.8.8:53",
+			},
+			valid: false,
+		},
+		{
+			name: "unknown scheme transport invalid",
+			options: Options{
+				Domain:     "example.com",
+				Threads:    1,
+				Transports: []string{"ftp://8.8.8.8:53"},
+			},
+			valid: false,
+		},
 		{
 			name: "all valid options",
 			options: Options{
@@ -76,7 +159,7 @@ func TestOptions_Validation(t *testing.T) {
 				Screenshots:  "/tmp/screenshots",
 				Threads:      10,
 				Whois:        true,
-				TLD:          "tlds.txt",
+				TLD:          []string{"tlds.txt"},
 				Nameservers:  "8.8.8.8:53,1.1.1.1:53",
 				UserAgent:    "Mozilla/5.0",
 			},
@@ -383,6 +466,60 @@ func TestResults_FormatAllFormats(t *testing.T) {
 	}
 }
 
+func TestResults_FormatStream(t *testing.T) {
+	results := Results{
+		{
+			Fuzzer: "original",
+			Domain: "example.com",
+			DNS: map[string][]string{
+				"A": {"93.184.216.34"},
+			},
+		},
+		{
+			Fuzzer: "addition",
+			Domain: "examplea.com",
+			DNS:    map[string][]string{},
+		},
+	}
+
+	for _, format := range []string{"json", "csv", "list", "cli"} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			write, finish := results.FormatStream(&buf, format)
+			for _, r := range results {
+				if err := write(r); err != nil {
+					t.Fatalf("write() error = %v", err)
+				}
+			}
+			if err := finish(); err != nil {
+				t.Fatalf("finish() error = %v", err)
+			}
+
+			streamed := buf.String()
+			if streamed == "" {
+				t.Fatalf("FormatStream(%s) produced no output", format)
+			}
+
+			if format == "json" {
+				var decoded []map[string]any
+				if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+					t.Fatalf("FormatStream(json) did not produce a valid JSON array: %v", err)
+				}
+				if len(decoded) != len(results) {
+					t.Errorf("len(decoded) = %d, want %d", len(decoded), len(results))
+				}
+			}
+
+			if format == "csv" {
+				batch := results.Format("csv")
+				if streamed != batch {
+					t.Errorf("FormatStream(csv) = %q, want it to match Format(csv) = %q", streamed, batch)
+				}
+			}
+		})
+	}
+}
+
 func TestResults_FormatEmptyResults(t *testing.T) {
 	results := Results{}
 
@@ -0,0 +1,171 @@
+package dnstwist
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ducksify/godnstwist/internal/scanner"
+	"github.com/miekg/dns"
+)
+
+// Resolver is the DNS-lookup surface Engine delegates to when
+// Options.Resolver is set, in place of the built-in resolver built from
+// Options.Nameservers/Transports. Lookup resolves name for qtype and
+// returns its answer-section records, or an error (including a "no such
+// host"-style error for NXDOMAIN).
+//
+// Implementing this lets library users integrate dnstwist with their own
+// resolver stack, add rate limiting, or record/replay DNS for reproducible
+// scans.
+type Resolver = scanner.ExternalResolver
+
+// defaultResolverCacheCapacity bounds the number of cached answers a
+// CachingResolver keeps, mirroring the scanner package's own answer cache.
+const defaultResolverCacheCapacity = 4096
+
+// defaultResolverNegativeTTL caps how long a failed Lookup is negative-cached
+// when none of its returned records carry a usable TTL, i.e. when the
+// upstream error didn't come with an SOA minimum to honor.
+const defaultResolverNegativeTTL = 60 * time.Second
+
+// CachingResolver wraps a Resolver with a TTL-aware LRU cache, so repeated
+// lookups for the same (name, qtype) across many fuzzer permutations that
+// share an answer (e.g. a parent zone's NS set) don't re-query the
+// underlying Resolver. Positive answers are cached for the minimum TTL
+// across their records; errors (including NXDOMAIN) are negative-cached for
+// DefaultNegativeTTL.
+type CachingResolver struct {
+	resolver Resolver
+	ttlCap   time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type cachingResolverEntry struct {
+	key     string
+	rrs     []dns.RR
+	err     error
+	expires time.Time
+}
+
+// NewCachingResolver wraps resolver with an LRU cache capping any cached
+// answer's lifetime at ttlCap, regardless of its reported TTL. ttlCap <= 0
+// uses DefaultResolverNegativeTTL as the cap.
+func NewCachingResolver(resolver Resolver, ttlCap time.Duration) *CachingResolver {
+	if ttlCap <= 0 {
+		ttlCap = defaultResolverNegativeTTL
+	}
+	return &CachingResolver{
+		resolver: resolver,
+		ttlCap:   ttlCap,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *CachingResolver) cacheKey(name string, qtype uint16) string {
+	return fmt.Sprintf("%s|%d", name, qtype)
+}
+
+// Lookup implements Resolver, serving from cache when a fresh entry exists
+// and delegating to the wrapped Resolver (storing its result) otherwise.
+func (c *CachingResolver) Lookup(ctx context.Context, name string, qtype uint16) ([]dns.RR, error) {
+	key := c.cacheKey(name, qtype)
+
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*cachingResolverEntry)
+		if time.Now().Before(entry.expires) {
+			c.order.MoveToFront(el)
+			c.mu.Unlock()
+			return entry.rrs, entry.err
+		}
+		c.order.Remove(el)
+		delete(c.entries, key)
+	}
+	c.mu.Unlock()
+
+	rrs, err := c.resolver.Lookup(ctx, name, qtype)
+	c.store(key, rrs, err)
+	return rrs, err
+}
+
+func (c *CachingResolver) store(key string, rrs []dns.RR, err error) {
+	ttl := defaultResolverNegativeTTL
+	if err == nil && len(rrs) > 0 {
+		min := rrs[0].Header().Ttl
+		for _, rr := range rrs[1:] {
+			if rr.Header().Ttl < min {
+				min = rr.Header().Ttl
+			}
+		}
+		ttl = time.Duration(min) * time.Second
+	}
+	if ttl <= 0 {
+		return
+	}
+	if ttl > c.ttlCap {
+		ttl = c.ttlCap
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el := c.order.PushFront(&cachingResolverEntry{key: key, rrs: rrs, err: err, expires: time.Now().Add(ttl)})
+	c.entries[key] = el
+
+	if c.order.Len() > defaultResolverCacheCapacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachingResolverEntry).key)
+	}
+}
+
+// MultiResolver fans a Lookup out to every configured Resolver concurrently
+// and returns the first non-error answer (happy-eyeballs-style), letting
+// the rest finish in the background. Returns the last error seen if every
+// Resolver fails.
+type MultiResolver struct {
+	resolvers []Resolver
+}
+
+// NewMultiResolver builds a MultiResolver over resolvers, queried in
+// parallel on every Lookup.
+func NewMultiResolver(resolvers ...Resolver) *MultiResolver {
+	return &MultiResolver{resolvers: resolvers}
+}
+
+// Lookup implements Resolver.
+func (m *MultiResolver) Lookup(ctx context.Context, name string, qtype uint16) ([]dns.RR, error) {
+	if len(m.resolvers) == 0 {
+		return nil, fmt.Errorf("dnstwist: MultiResolver has no resolvers configured")
+	}
+
+	type answer struct {
+		rrs []dns.RR
+		err error
+	}
+
+	results := make(chan answer, len(m.resolvers))
+	for _, r := range m.resolvers {
+		go func(r Resolver) {
+			rrs, err := r.Lookup(ctx, name, qtype)
+			results <- answer{rrs: rrs, err: err}
+		}(r)
+	}
+
+	var lastErr error
+	for range m.resolvers {
+		a := <-results
+		if a.err == nil {
+			return a.rrs, nil
+		}
+		lastErr = a.err
+	}
+	return nil, lastErr
+}
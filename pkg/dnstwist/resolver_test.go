@@ -0,0 +1,137 @@
+package dnstwist
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// stubResolver answers every Lookup from a canned (rrs, err) pair and counts
+// how many times it was called.
+type stubResolver struct {
+	rrs   []dns.RR
+	err   error
+	delay time.Duration
+	calls int32
+}
+
+func (s *stubResolver) Lookup(ctx context.Context, name string, qtype uint16) ([]dns.RR, error) {
+	atomic.AddInt32(&s.calls, 1)
+	if s.delay > 0 {
+		time.Sleep(s.delay)
+	}
+	return s.rrs, s.err
+}
+
+func mustRR(t *testing.T, s string) dns.RR {
+	t.Helper()
+	rr, err := dns.NewRR(s)
+	if err != nil {
+		t.Fatalf("dns.NewRR(%q) error = %v", s, err)
+	}
+	return rr
+}
+
+func TestCachingResolver_CachesPositiveAnswer(t *testing.T) {
+	a := mustRR(t, "example.com. 300 IN A 93.184.216.34")
+	stub := &stubResolver{rrs: []dns.RR{a}}
+	cr := NewCachingResolver(stub, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		rrs, err := cr.Lookup(context.Background(), "example.com", dns.TypeA)
+		if err != nil {
+			t.Fatalf("Lookup() error = %v", err)
+		}
+		if len(rrs) != 1 {
+			t.Fatalf("Lookup() returned %d records, want 1", len(rrs))
+		}
+	}
+
+	if calls := atomic.LoadInt32(&stub.calls); calls != 1 {
+		t.Errorf("underlying resolver called %d times, want 1 (cached)", calls)
+	}
+}
+
+func TestCachingResolver_NegativeCachesError(t *testing.T) {
+	stub := &stubResolver{err: fmt.Errorf("no such host")}
+	cr := NewCachingResolver(stub, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		if _, err := cr.Lookup(context.Background(), "nx.example.com", dns.TypeA); err == nil {
+			t.Fatalf("Lookup() error = nil, want error")
+		}
+	}
+
+	if calls := atomic.LoadInt32(&stub.calls); calls != 1 {
+		t.Errorf("underlying resolver called %d times, want 1 (negative cached)", calls)
+	}
+}
+
+func TestCachingResolver_ExpiresAfterTTL(t *testing.T) {
+	stub := &stubResolver{err: fmt.Errorf("temporary failure")}
+	cr := NewCachingResolver(stub, 10*time.Millisecond)
+
+	if _, err := cr.Lookup(context.Background(), "example.com", dns.TypeA); err == nil {
+		t.Fatalf("Lookup() error = nil, want error")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, err := cr.Lookup(context.Background(), "example.com", dns.TypeA); err == nil {
+		t.Fatalf("Lookup() error = nil, want error")
+	}
+
+	if calls := atomic.LoadInt32(&stub.calls); calls != 2 {
+		t.Errorf("underlying resolver called %d times, want 2 (cache expired)", calls)
+	}
+}
+
+func TestMultiResolver_ReturnsFastestSuccess(t *testing.T) {
+	slow := &stubResolver{err: fmt.Errorf("slow resolver failed"), delay: 20 * time.Millisecond}
+	fast := &stubResolver{rrs: []dns.RR{mustRR(t, "example.com. 300 IN A 93.184.216.34")}}
+
+	mr := NewMultiResolver(slow, fast)
+	rrs, err := mr.Lookup(context.Background(), "example.com", dns.TypeA)
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if len(rrs) != 1 {
+		t.Fatalf("Lookup() returned %d records, want 1", len(rrs))
+	}
+}
+
+func TestMultiResolver_AllFail(t *testing.T) {
+	a := &stubResolver{err: fmt.Errorf("resolver a failed")}
+	b := &stubResolver{err: fmt.Errorf("resolver b failed")}
+
+	mr := NewMultiResolver(a, b)
+	if _, err := mr.Lookup(context.Background(), "example.com", dns.TypeA); err == nil {
+		t.Fatalf("Lookup() error = nil, want error")
+	}
+}
+
+func TestMultiResolver_NoResolvers(t *testing.T) {
+	mr := NewMultiResolver()
+	if _, err := mr.Lookup(context.Background(), "example.com", dns.TypeA); err == nil {
+		t.Fatalf("Lookup() error = nil, want error")
+	}
+}
+
+func TestNew_PrefersResolverOverNameservers(t *testing.T) {
+	stub := &stubResolver{rrs: []dns.RR{mustRR(t, "example.com. 300 IN A 93.184.216.34")}}
+
+	engine, err := New(Options{
+		Domain:      "example.com",
+		Threads:     1,
+		Nameservers: "not a valid nameserver !!!",
+		Resolver:    stub,
+	})
+	if err != nil {
+		t.Fatalf("New() with Resolver set error = %v, want nil (nameserver validation should be skipped)", err)
+	}
+	if engine == nil {
+		t.Fatalf("New() returned nil engine")
+	}
+}
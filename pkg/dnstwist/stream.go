@@ -0,0 +1,117 @@
+package dnstwist
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ducksify/godnstwist/internal/ct"
+)
+
+// Stream behaves like GetResults but emits each Result on the returned
+// channel as soon as its scan stages complete, instead of collecting every
+// permutation into memory first. This scales to permutation sets (e.g.
+// bitsquatting x homoglyph x TLD-swap) too large to comfortably buffer
+// whole. The worker pool scanning domains is sized by Options.Threads.
+//
+// Both channels are closed once every domain has been scanned, ctx is
+// canceled, or a setup error (permutation generation, CT query) occurs. A
+// setup error is sent on the error channel before both channels close;
+// per-domain scan errors are not surfaced here, matching GetResults, which
+// also returns partial domains rather than failing the whole scan.
+func (e *Engine) Stream(ctx context.Context) (<-chan Result, <-chan error) {
+	results := make(chan Result)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(results)
+		defer close(errs)
+
+		e.mu.Lock()
+		if err := e.fuzzer.Generate(e.options.Fuzzers); err != nil {
+			e.mu.Unlock()
+			errs <- fmt.Errorf("failed to generate permutations: %v", err)
+			return
+		}
+
+		if e.options.CT {
+			names, err := ct.NewClient().Query(domainPart(e.options.Domain))
+			if err != nil {
+				e.mu.Unlock()
+				errs <- fmt.Errorf("failed to query Certificate Transparency logs: %v", err)
+				return
+			}
+			e.fuzzer.AddExternal("ct", names)
+		}
+
+		candidates := e.fuzzer.Domains()
+
+		if e.options.ZoneWalk {
+			wordlist, err := readDictionaryFile(e.options.Dictionary)
+			if err != nil {
+				e.mu.Unlock()
+				errs <- fmt.Errorf("failed to read zone-walk dictionary: %v", err)
+				return
+			}
+			candidates = append(candidates, e.scanner.ZoneWalk(e.options.Domain, wordlist)...)
+		}
+		e.mu.Unlock()
+
+		recordType := strings.ToUpper(strings.TrimSpace(e.options.RegisteredBy))
+
+		for domain := range e.scanner.ScanStream(ctx, candidates) {
+			hasA := len(domain.DNS["A"]) > 0
+			hasAAAA := len(domain.DNS["AAAA"]) > 0
+			hasNS := len(domain.DNS["NS"]) > 0
+			var isRegistered bool
+			switch recordType {
+			case "A":
+				isRegistered = hasA || hasAAAA
+			case "NS":
+				isRegistered = hasNS
+			default:
+				isRegistered = hasA || hasAAAA || hasNS
+			}
+
+			if e.options.Registered && !isRegistered {
+				continue
+			}
+			if e.options.Unregistered && isRegistered {
+				continue
+			}
+
+			var punycode, unicodeForm string
+			if containsNonASCII(domain.Domain) {
+				punycode = domain.Punycode
+				unicodeForm = domain.Domain
+			}
+
+			result := Result{
+				Fuzzer:        domain.Fuzzer,
+				Domain:        domain.Domain,
+				Punycode:      punycode,
+				Unicode:       unicodeForm,
+				Cyrillic:      domain.Cyrillic,
+				DNS:           domain.DNS,
+				GeoIP:         domain.GeoIP,
+				Banner:        domain.Banner,
+				Whois:         domain.Whois,
+				LSH:           domain.LSH,
+				PHash:         domain.PHash,
+				DNSSEC:        domain.DNSSEC,
+				EDNS:          domain.EDNS,
+				EmailAuth:     domain.EmailAuth,
+				ResolvedBy:    domain.ResolvedBy,
+				Confusability: domain.Confusability,
+			}
+
+			select {
+			case results <- result:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results, errs
+}